@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNoEndOfLifeData = errors.New("no end-of-life data")
+
+// EndOfLifeCycle is the release cycle of a product a package version belongs to e.g. Node.js 18.
+type EndOfLifeCycle struct {
+	Product string
+	Cycle   string
+	// EOL is set if the cycle is known to be end-of-life without a specific date
+	EOL     bool
+	EOLDate time.Time
+	// Latest is the latest release of the cycle if known
+	Latest string
+}
+
+// IsEOL tells whether the cycle reached its end-of-life at the given point in time.
+func (c EndOfLifeCycle) IsEOL(at time.Time) bool {
+	return c.EOL || (!c.EOLDate.IsZero() && !at.Before(c.EOLDate))
+}
+
+// EndOfLifeSource looks up the release cycle of package versions identified by their package URLs.
+type EndOfLifeSource interface {
+	// EndOfLifeFor returns ErrNoEndOfLifeData if the package or its version is unknown to the source.
+	EndOfLifeFor(ctx context.Context, packageUrl string) (*EndOfLifeCycle, error)
+}
@@ -3,20 +3,47 @@ package ports
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var ErrNoKVEntryForKey = errors.New("no entry found")
 
+// KeyValueEntry is a single entry of a KeyValueStore including its bookkeeping timestamps.
+// A zero StoredAt means the store could not determine when the entry was written,
+// a zero ExpiresAt means the entry never expires.
+type KeyValueEntry struct {
+	Key       []byte
+	Value     []byte
+	StoredAt  time.Time
+	ExpiresAt time.Time
+}
+
 type KeyValueReader interface {
 	Get(ctx context.Context, key []byte) ([]byte, error)
 }
 
+type KeyValueIterator interface {
+	// Iterate calls fn for every live entry whose key starts with prefix.
+	// Iteration stops at the first error returned by fn.
+	Iterate(ctx context.Context, prefix []byte, fn func(entry KeyValueEntry) error) error
+}
+
 type KeyValueWriter interface {
 	Put(ctx context.Context, key, value []byte) error
+	// PutEntry stores the entry with the given StoredAt and ExpiresAt timestamps
+	// instead of deriving them from the store's defaults.
+	PutEntry(ctx context.Context, entry KeyValueEntry) error
+}
+
+type KeyValueDeleter interface {
+	// Delete removes the entry for key - deleting a missing key is not an error.
+	Delete(ctx context.Context, key []byte) error
 }
 
 type KeyValueStore interface {
 	KeyValueReader
+	KeyValueIterator
 	KeyValueWriter
+	KeyValueDeleter
 	Close() error
 }
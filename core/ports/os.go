@@ -3,3 +3,5 @@ package ports
 import "io"
 
 type STDOUT io.Writer
+
+type STDERR io.Writer
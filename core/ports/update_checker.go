@@ -8,10 +8,12 @@ import (
 )
 
 var (
+	ErrInvalidPackageURL               = errors.New("invalid package URL")
 	ErrNoCheckerForPackageType         = errors.New("no checker for package type")
 	ErrNoMatchingPackageFound          = errors.New("no matching package found")
 	ErrAmbiguousPackageFound           = errors.New("ambiguous package found")
 	ErrCurrentVersionGreaterThanLatest = errors.New("current version is greater than latest version")
+	ErrNotModified                     = errors.New("package metadata not modified")
 )
 
 type PackageInfo struct {
@@ -20,9 +22,103 @@ type PackageInfo struct {
 	LatestVersion  string
 	CurrentVersion string
 	PackageManager string
+	// LatestPreReleaseVersion is set by registries distinguishing pre-releases if one is newer than LatestVersion
+	LatestPreReleaseVersion string `json:",omitempty"`
+	// LatestAppVersion is the version of the application packaged by the latest version e.g. the appVersion of Helm charts
+	LatestAppVersion string `json:",omitempty"`
+	// LatestRevision identifies the latest revision of the latest version e.g. the recipe revision of Conan packages
+	LatestRevision string `json:",omitempty"`
+	// Deprecated is set if the registry marks the package or its current version as deprecated,
+	// DeprecationMessage and Replacement are optional details provided by the registry
+	Deprecated         bool   `json:",omitempty"`
+	DeprecationMessage string `json:",omitempty"`
+	Replacement        string `json:",omitempty"`
+	// Yanked is set if the current version was yanked or unlisted from the registry
+	Yanked       bool   `json:",omitempty"`
+	YankedReason string `json:",omitempty"`
+	// CurrentLicense and LatestLicense are the SPDX expressions declared for the respective versions - empty if unknown
+	CurrentLicense string `json:",omitempty"`
+	LatestLicense  string `json:",omitempty"`
+	// CacheStatus is set by lookups to tell how the result was served - it is never cached itself
+	CacheStatus CacheStatus `json:"-"`
 }
 
 type UpdateChecker interface {
 	LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*PackageInfo, error)
 	SupportedPackageType() string
 }
+
+// CacheValidators are the HTTP validators of a previous registry response.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (v CacheValidators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ConditionalUpdateChecker is implemented by checkers supporting conditional requests
+// to revalidate previously cached results.
+type ConditionalUpdateChecker interface {
+	UpdateChecker
+	// LatestVersionIfModified returns ErrNotModified if the registry did not change since
+	// the given validators were issued.
+	LatestVersionIfModified(
+		ctx context.Context,
+		packageUrl packageurl.PackageURL,
+		validators CacheValidators,
+	) (*PackageInfo, CacheValidators, error)
+}
+
+// PackageLookup resolves the latest version of a package identified by its package URL
+// independent of the package type.
+type PackageLookup interface {
+	LatestVersionFor(ctx context.Context, packageUrl string) (*PackageInfo, error)
+}
+
+// CacheStatus describes how a lookup was served from the cache.
+type CacheStatus string
+
+const (
+	CacheStatusMiss  CacheStatus = "miss"
+	CacheStatusHit   CacheStatus = "hit"
+	CacheStatusStale CacheStatus = "stale"
+)
+
+// LookupObserver gets notified about every finished lookup e.g. to record metrics.
+type LookupObserver interface {
+	ObserveLookup(packageType string, cacheStatus CacheStatus, err error)
+}
+
+// LookupOutcome classifies the result of a lookup.
+type LookupOutcome string
+
+const (
+	LookupOutcomeFound       LookupOutcome = "found"
+	LookupOutcomeNotModified LookupOutcome = "not_modified"
+	LookupOutcomeNotFound    LookupOutcome = "not_found"
+	LookupOutcomeAmbiguous   LookupOutcome = "ambiguous"
+	LookupOutcomeUnsupported LookupOutcome = "unsupported"
+	LookupOutcomeInvalid     LookupOutcome = "invalid"
+	LookupOutcomeError       LookupOutcome = "error"
+)
+
+func LookupOutcomeFor(err error) LookupOutcome {
+	switch {
+	case err == nil:
+		return LookupOutcomeFound
+	case errors.Is(err, ErrNotModified):
+		return LookupOutcomeNotModified
+	case errors.Is(err, ErrNoMatchingPackageFound):
+		return LookupOutcomeNotFound
+	case errors.Is(err, ErrAmbiguousPackageFound):
+		return LookupOutcomeAmbiguous
+	case errors.Is(err, ErrNoCheckerForPackageType):
+		return LookupOutcomeUnsupported
+	case errors.Is(err, ErrInvalidPackageURL):
+		return LookupOutcomeInvalid
+	default:
+		return LookupOutcomeError
+	}
+}
@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Vulnerability is a known vulnerability affecting a specific package version.
+type Vulnerability struct {
+	ID string `json:"id"`
+	// Source is the name of the database the vulnerability was found in and URL links to its entry
+	Source     string                  `json:"source"`
+	URL        string                  `json:"url,omitempty"`
+	Aliases    []string                `json:"aliases,omitempty"`
+	Summary    string                  `json:"summary,omitempty"`
+	Details    string                  `json:"details,omitempty"`
+	Severities []VulnerabilitySeverity `json:"severities,omitempty"`
+	References []string                `json:"references,omitempty"`
+	Published  time.Time               `json:"published"`
+	Modified   time.Time               `json:"modified"`
+	// FixedVersions lists all versions of the affected package that fix the vulnerability
+	FixedVersions []string `json:"fixed_versions,omitempty"`
+}
+
+// VulnerabilitySeverity is a severity score e.g. a CVSS vector.
+type VulnerabilitySeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// VulnerabilityReport contains all known vulnerabilities of a single package version.
+type VulnerabilityReport struct {
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	// FirstNonVulnerableVersion is the lowest version greater than the current one not affected by
+	// any of the vulnerabilities - empty if the package is not vulnerable or no such version is known.
+	FirstNonVulnerableVersion string `json:"first_non_vulnerable_version,omitempty"`
+}
+
+// VulnerabilitySource looks up known vulnerabilities of package versions identified by their package URLs.
+type VulnerabilitySource interface {
+	// VulnerabilitiesFor returns a report for every given package URL - package URLs without version are skipped.
+	VulnerabilitiesFor(ctx context.Context, packageUrls []string) (map[string]VulnerabilityReport, error)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+var tracer = otel.Tracer("github.com/prskr/aucs/core/services")
+
+// BOMEnricher adds the latest available version of every component as property to a BOM.
+type BOMEnricher struct {
+	Lookup      ports.PackageLookup
+	Parallelism uint8
+	// Timeout limits the duration of a single Enrich call - 0 disables the limit
+	Timeout time.Duration
+	// Vulnerabilities is optional - if set known vulnerabilities of the current versions are added to the BOM
+	Vulnerabilities ports.VulnerabilitySource
+	// EndOfLife is optional - if set components are checked whether their release cycle reached its end-of-life
+	EndOfLife ports.EndOfLifeSource
+	// Version of aucs recorded in the BOM metadata
+	Version string
+}
+
+// Enrich looks up all components of the BOM and returns a summary of the individual outcomes.
+// Failed lookups are recorded in the BOM and the summary - only fatal errors i.e. the cancellation of ctx
+// or exceeding the timeout abort the enrichment. The BOM must not be used in that case.
+// Results are always applied in component order independent of the completion order of the lookups.
+// Components without package URL are left untouched.
+func (e BOMEnricher) Enrich(ctx context.Context, bom *cyclonedx.BOM) (EnrichSummary, error) {
+	ctx, span := tracer.Start(ctx, "BOMEnricher.Enrich")
+	defer span.End()
+
+	start := time.Now()
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	var components []cyclonedx.Component
+	if bom.Components != nil {
+		components = *bom.Components
+	}
+
+	span.SetAttributes(attribute.Int("aucs.bom.components", len(components)))
+
+	// vulnerabilities of previous runs are replaced - bom-refs are assigned again if components are still vulnerable
+	removeVulnerabilities(bom)
+	for i := range components {
+		removeBOMRef(&components[i])
+	}
+
+	results := make([]ComponentResult, len(components))
+	for i := range components {
+		results[i] = ComponentResult{PackageURL: components[i].PackageURL, Outcome: ComponentOutcomeCanceled}
+	}
+
+	grp, grpCtx := errgroup.WithContext(ctx)
+	grp.SetLimit(int(max(e.Parallelism, 1)))
+
+	for i := range components {
+		// stop scheduling further lookups as soon as the run is canceled
+		if grpCtx.Err() != nil {
+			break
+		}
+
+		grp.Go(func() (err error) {
+			results[i], err = e.processComponent(grpCtx, &components[i])
+			return err
+		})
+	}
+
+	// scheduling might have stopped before any running lookup noticed the cancellation
+	err := grp.Wait()
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return newEnrichSummary(results, time.Since(start)), fmt.Errorf("enrichment aborted: %w", err)
+	}
+
+	var extraMetadata []cyclonedx.Property
+
+	if e.Vulnerabilities != nil {
+		status, err := e.enrichVulnerabilities(ctx, bom)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return newEnrichSummary(results, time.Since(start)), fmt.Errorf("enrichment aborted: %w", err)
+		}
+
+		extraMetadata = append(extraMetadata, cyclonedx.Property{Name: PropertyVulnerabilityStatus, Value: status})
+	}
+
+	e.recordMetadata(bom, results, time.Now(), extraMetadata...)
+
+	return newEnrichSummary(results, time.Since(start)), nil
+}
+
+func (e BOMEnricher) processComponent(ctx context.Context, in *cyclonedx.Component) (ComponentResult, error) {
+	// there's nothing to look up for components without package URL e.g. files or operating systems
+	if in.PackageURL == "" {
+		return ComponentResult{Outcome: ComponentOutcomeSkipped}, nil
+	}
+
+	ctx, span := tracer.Start(ctx, "BOMEnricher.ProcessComponent", trace.WithAttributes(
+		attribute.String("aucs.package_url", in.PackageURL),
+	))
+	defer span.End()
+
+	info, err := e.Lookup.LatestVersionFor(ctx, in.PackageURL)
+
+	// the lookup most likely failed because the run was canceled - leave the component untouched
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		span.SetStatus(codes.Error, ctxErr.Error())
+		return ComponentResult{PackageURL: in.PackageURL, Outcome: ComponentOutcomeCanceled}, ctxErr
+	}
+
+	result := ComponentResult{
+		PackageURL: in.PackageURL,
+		Outcome:    ComponentOutcomeFor(info, err),
+	}
+
+	if purl, err := packageurl.FromString(in.PackageURL); err == nil {
+		result.Ecosystem = purl.Type
+	}
+
+	span.SetAttributes(attribute.String("aucs.component.outcome", string(result.Outcome)))
+
+	// end-of-life data doesn't depend on the registry e.g. for runtimes without checker
+	eolProperties := e.endOfLifeProperties(ctx, in.PackageURL, time.Now())
+
+	if err != nil {
+		result.Error = err.Error()
+		span.SetStatus(codes.Error, err.Error())
+		slog.WarnContext(ctx, "Failed to determine latest version for package",
+			slog.String("package_url", in.PackageURL),
+			slog.String("outcome", string(result.Outcome)),
+			slog.String("err", err.Error()),
+		)
+
+		replaceProperties(&in.Properties, append([]cyclonedx.Property{
+			{Name: PropertyLookupStatus, Value: string(result.Outcome)},
+			{Name: PropertyLookupError, Value: result.Error},
+		}, eolProperties...)...)
+
+		return result, nil
+	}
+
+	slog.DebugContext(ctx, "Found latest package version",
+		slog.String("package_url", in.PackageURL),
+		slog.String("latest_version", info.LatestVersion),
+		slog.String("current_version", in.Version),
+	)
+
+	props := []cyclonedx.Property{
+		{Name: PropertyLatestVersion, Value: info.LatestVersion},
+		{Name: PropertyLookupStatus, Value: string(result.Outcome)},
+	}
+	props = appendNonEmpty(props, PropertyLatestPreReleaseVersion, info.LatestPreReleaseVersion)
+	props = appendNonEmpty(props, PropertyLatestAppVersion, info.LatestAppVersion)
+	props = appendNonEmpty(props, PropertyLatestRevision, info.LatestRevision)
+	props = append(props, deprecationProperties(info)...)
+	props = append(props, licenseProperties(info)...)
+
+	replaceProperties(&in.Properties, append(props, eolProperties...)...)
+
+	return result, nil
+}
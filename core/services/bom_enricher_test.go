@@ -0,0 +1,283 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/core/services"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var _ ports.PackageLookup = (fakeLookup)(nil)
+
+type fakeLookup map[string]*ports.PackageInfo
+
+func (f fakeLookup) LatestVersionFor(_ context.Context, packageUrl string) (*ports.PackageInfo, error) {
+	switch packageUrl {
+	case "pkg:golang/github.com/prskr/aucs@v0.1.0":
+		return nil, fmt.Errorf("%w: golang", ports.ErrNoCheckerForPackageType)
+	case "pkg:npm/left-pad@1.0.0":
+		return nil, fmt.Errorf("failed to reach registry: %w", context.DeadlineExceeded)
+	}
+
+	info, ok := f[packageUrl]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoMatchingPackageFound, packageUrl)
+	}
+
+	return info, nil
+}
+
+func TestBOMEnricher_Enrich_Summary(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup: fakeLookup{
+			"pkg:npm/is-even-ai@1.0.1": {LatestVersion: "1.0.5"},
+			"pkg:npm/is-odd-ai@1.0.1":  {LatestVersion: "1.0.3", CacheStatus: ports.CacheStatusHit},
+		},
+		Parallelism: 2,
+		Version:     "v1.2.3",
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &[]cyclonedx.Component{
+		{PackageURL: "pkg:npm/is-even-ai@1.0.1"},
+		{PackageURL: "pkg:npm/is-odd-ai@1.0.1"},
+		{PackageURL: "pkg:golang/github.com/prskr/aucs@v0.1.0"},
+		{PackageURL: "pkg:npm/is-number-ai@1.0.0"},
+		{PackageURL: "pkg:npm/left-pad@1.0.0"},
+		{Type: cyclonedx.ComponentTypeOS, Name: "debian", Version: "12"},
+	}
+
+	summary, err := enricher.Enrich(testx.Context(t), bom)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 6, summary.Components)
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeEnriched])
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeCached])
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeUnsupported])
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeNotFound])
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeError])
+	assert.Equal(t, 1, summary.Outcomes[services.ComponentOutcomeSkipped])
+	assert.Len(t, summary.Failures, 2)
+	assert.InDelta(t, 0.5, summary.ErrorRatio(), 0.001)
+
+	assert.Equal(t, []cyclonedx.Property{
+		{Name: services.PropertyLatestVersion, Value: "1.0.5"},
+		{Name: services.PropertyLookupStatus, Value: "enriched"},
+	}, *(*bom.Components)[0].Properties)
+
+	assert.Equal(t, []cyclonedx.Property{
+		{Name: services.PropertyLookupStatus, Value: "not_found"},
+		{Name: services.PropertyLookupError, Value: "no matching package found: pkg:npm/is-number-ai@1.0.0"},
+	}, *(*bom.Components)[3].Properties)
+
+	if assert.NotNil(t, bom.Metadata) && assert.NotNil(t, bom.Metadata.Tools) && assert.NotNil(t, bom.Metadata.Tools.Components) {
+		assert.Equal(t, "aucs", (*bom.Metadata.Tools.Components)[0].Name)
+		assert.Equal(t, "v1.2.3", (*bom.Metadata.Tools.Components)[0].Version)
+	}
+
+	assert.Contains(t, *bom.Metadata.Properties, cyclonedx.Property{Name: services.PropertyRegistries, Value: "npm"})
+	assert.Contains(t, *bom.Metadata.Properties, cyclonedx.Property{Name: services.PropertyVersion, Value: "v1.2.3"})
+}
+
+func TestBOMEnricher_Enrich_WithoutPackageURL(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup:      fakeLookup{},
+		Parallelism: 2,
+	}
+
+	properties := []cyclonedx.Property{{Name: "vendor:origin", Value: "base-image"}}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &[]cyclonedx.Component{
+		{Type: cyclonedx.ComponentTypeOS, Name: "debian", Version: "12"},
+		{Type: cyclonedx.ComponentTypeFile, Name: "/etc/os-release", Properties: &properties},
+	}
+
+	summary, err := enricher.Enrich(testx.Context(t), bom)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 2, summary.Outcomes[services.ComponentOutcomeSkipped])
+	assert.Empty(t, summary.Failures)
+	assert.Zero(t, summary.ErrorRatio())
+
+	assert.Nil(t, (*bom.Components)[0].Properties)
+	assert.Equal(t, []cyclonedx.Property{{Name: "vendor:origin", Value: "base-image"}}, *(*bom.Components)[1].Properties)
+}
+
+func TestBOMEnricher_Enrich_Timeout(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup:      blockingLookup{},
+		Parallelism: 2,
+		Timeout:     50 * time.Millisecond,
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &[]cyclonedx.Component{
+		{PackageURL: "pkg:npm/is-even-ai@1.0.1"},
+		{PackageURL: "pkg:npm/is-odd-ai@1.0.1"},
+		{PackageURL: "pkg:npm/is-number-ai@1.0.0"},
+	}
+
+	summary, err := enricher.Enrich(testx.Context(t), bom)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 3, summary.Outcomes[services.ComponentOutcomeCanceled])
+	assert.Nil(t, (*bom.Components)[0].Properties)
+	assert.Nil(t, bom.Metadata)
+}
+
+var _ ports.PackageLookup = (*blockingLookup)(nil)
+
+// blockingLookup blocks until the context is done
+type blockingLookup struct{}
+
+func (blockingLookup) LatestVersionFor(ctx context.Context, _ string) (*ports.PackageInfo, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBOMEnricher_Enrich_Vulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup:      fakeLookup{"pkg:npm/lodash@4.17.15": {LatestVersion: "4.17.21"}},
+		Parallelism: 1,
+		Vulnerabilities: fakeVulnerabilitySource{
+			"pkg:npm/lodash@4.17.15": {
+				Vulnerabilities:           []ports.Vulnerability{{ID: "GHSA-p6mc-m468-83gw", Source: "OSV", FixedVersions: []string{"4.17.19"}}},
+				FirstNonVulnerableVersion: "4.17.19",
+			},
+		},
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &[]cyclonedx.Component{
+		{Name: "lodash", Version: "4.17.15", PackageURL: "pkg:npm/lodash@4.17.15"},
+		{BOMRef: "pkg:npm/minimist@1.2.8", Name: "minimist", Version: "1.2.8", PackageURL: "pkg:npm/minimist@1.2.8"},
+	}
+
+	for range 2 {
+		_, err := enricher.Enrich(testx.Context(t), bom)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	component := (*bom.Components)[0]
+	assert.Equal(t, "pkg:npm/lodash@4.17.15", component.BOMRef)
+	assert.Contains(t, *component.Properties, cyclonedx.Property{Name: services.PropertyFirstNonVulnerableVersion, Value: "4.17.19"})
+	assert.Contains(t, *bom.Metadata.Properties, cyclonedx.Property{Name: services.PropertyVulnerabilityStatus, Value: "checked"})
+
+	if assert.NotNil(t, bom.Vulnerabilities) && assert.Len(t, *bom.Vulnerabilities, 1) {
+		vuln := (*bom.Vulnerabilities)[0]
+		assert.Equal(t, "GHSA-p6mc-m468-83gw", vuln.ID)
+		assert.Equal(t, []cyclonedx.Affects{{
+			Ref:   "pkg:npm/lodash@4.17.15",
+			Range: &[]cyclonedx.AffectedVersions{{Version: "4.17.15", Status: cyclonedx.VulnerabilityStatusAffected}},
+		}}, *vuln.Affects)
+	}
+
+	services.StripBOM(bom)
+
+	assert.Nil(t, bom.Vulnerabilities)
+	assert.Empty(t, (*bom.Components)[0].BOMRef)
+	assert.Nil(t, (*bom.Components)[0].Properties)
+	assert.Equal(t, "pkg:npm/minimist@1.2.8", (*bom.Components)[1].BOMRef)
+}
+
+var _ ports.VulnerabilitySource = (fakeVulnerabilitySource)(nil)
+
+type fakeVulnerabilitySource map[string]ports.VulnerabilityReport
+
+func (f fakeVulnerabilitySource) VulnerabilitiesFor(_ context.Context, packageUrls []string) (map[string]ports.VulnerabilityReport, error) {
+	reports := make(map[string]ports.VulnerabilityReport)
+	for _, purl := range packageUrls {
+		reports[purl] = f[purl]
+	}
+
+	return reports, nil
+}
+
+func TestBOMEnricher_Enrich_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup: fakeLookup{
+			"pkg:npm/request@2.88.0": {
+				LatestVersion:           "2.88.2",
+				LatestPreReleaseVersion: "3.0.0-beta.1",
+				LatestAppVersion:        "2.88.2",
+				LatestRevision:          "b4b5d1f1b3c25e4a7c0c6b4a1b1c0e2d",
+				Deprecated:              true,
+				DeprecationMessage:      "request has been deprecated",
+				Yanked:                  true,
+				CurrentLicense:          "Apache-2.0",
+				LatestLicense:           "BUSL-1.1",
+			},
+		},
+		Parallelism: 1,
+		EndOfLife: fakeEndOfLifeSource{
+			"pkg:generic/node@0.10.1": {Product: "nodejs", Cycle: "0.10", EOL: true},
+			"pkg:npm/request@2.88.0":  {Product: "request", Cycle: "2", EOLDate: time.Date(2999, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &[]cyclonedx.Component{
+		{Name: "request", Version: "2.88.0", PackageURL: "pkg:npm/request@2.88.0"},
+		{Name: "node", Version: "0.10.1", PackageURL: "pkg:generic/node@0.10.1"},
+	}
+
+	if _, err := enricher.Enrich(testx.Context(t), bom); !assert.NoError(t, err) {
+		return
+	}
+
+	request := *(*bom.Components)[0].Properties
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyLatestPreReleaseVersion, Value: "3.0.0-beta.1"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyLatestAppVersion, Value: "2.88.2"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyLatestRevision, Value: "b4b5d1f1b3c25e4a7c0c6b4a1b1c0e2d"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyDeprecated, Value: "true"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyDeprecationMessage, Value: "request has been deprecated"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyYanked, Value: "true"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyEOL, Value: "false"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyEOLDate, Value: "2999-01-01"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyCurrentLicense, Value: "Apache-2.0"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyLatestLicense, Value: "BUSL-1.1"})
+	assert.Contains(t, request, cyclonedx.Property{Name: services.PropertyLicenseChanged, Value: "true"})
+
+	// the lookup of the node component fails but the end-of-life data is recorded anyway
+	node := *(*bom.Components)[1].Properties
+	assert.Contains(t, node, cyclonedx.Property{Name: services.PropertyEOL, Value: "true"})
+	assert.Contains(t, node, cyclonedx.Property{Name: services.PropertyEOLCycle, Value: "nodejs@0.10"})
+	assert.NotContains(t, node, cyclonedx.Property{Name: services.PropertyDeprecated, Value: "true"})
+}
+
+var _ ports.EndOfLifeSource = (fakeEndOfLifeSource)(nil)
+
+type fakeEndOfLifeSource map[string]ports.EndOfLifeCycle
+
+func (f fakeEndOfLifeSource) EndOfLifeFor(_ context.Context, packageUrl string) (*ports.EndOfLifeCycle, error) {
+	cycle, ok := f[packageUrl]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoEndOfLifeData, packageUrl)
+	}
+
+	return &cycle, nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/spdx"
+)
+
+const (
+	PropertyCurrentLicense = "aucs:package:current_license"
+	PropertyLatestLicense  = "aucs:package:latest_license"
+	PropertyLicenseChanged = "aucs:package:license_changed"
+)
+
+// licenseProperties records the licenses declared for the current and latest version.
+// Whether the license changed is only recorded if both licenses are known - their notation is ignored.
+func licenseProperties(info *ports.PackageInfo) (props []cyclonedx.Property) {
+	props = appendNonEmpty(props, PropertyCurrentLicense, info.CurrentLicense)
+	props = appendNonEmpty(props, PropertyLatestLicense, info.LatestLicense)
+
+	if info.CurrentLicense != "" && info.LatestLicense != "" {
+		changed := !spdx.Equal(info.CurrentLicense, info.LatestLicense)
+		props = append(props, cyclonedx.Property{Name: PropertyLicenseChanged, Value: strconv.FormatBool(changed)})
+	}
+
+	return props
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const (
+	PropertyDeprecated         = "aucs:package:deprecated"
+	PropertyDeprecationMessage = "aucs:package:deprecation_message"
+	PropertyReplacement        = "aucs:package:replacement"
+	PropertyYanked             = "aucs:package:yanked"
+	PropertyYankedReason       = "aucs:package:yanked_reason"
+	PropertyEOL                = "aucs:package:eol"
+	PropertyEOLCycle           = "aucs:package:eol_cycle"
+	PropertyEOLDate            = "aucs:package:eol_date"
+)
+
+// deprecationProperties only records deprecated or yanked packages - all other packages don't get any property.
+func deprecationProperties(info *ports.PackageInfo) (props []cyclonedx.Property) {
+	if info.Deprecated {
+		props = append(props, cyclonedx.Property{Name: PropertyDeprecated, Value: strconv.FormatBool(true)})
+		props = appendNonEmpty(props, PropertyDeprecationMessage, info.DeprecationMessage)
+		props = appendNonEmpty(props, PropertyReplacement, info.Replacement)
+	}
+
+	if info.Yanked {
+		props = append(props, cyclonedx.Property{Name: PropertyYanked, Value: strconv.FormatBool(true)})
+		props = appendNonEmpty(props, PropertyYankedReason, info.YankedReason)
+	}
+
+	return props
+}
+
+// endOfLifeProperties records whether the release cycle of the component reached its end-of-life at the given time.
+// Components unknown to the source don't get any property, failing lookups are only logged.
+func (e BOMEnricher) endOfLifeProperties(ctx context.Context, packageUrl string, at time.Time) []cyclonedx.Property {
+	if e.EndOfLife == nil {
+		return nil
+	}
+
+	cycle, err := e.EndOfLife.EndOfLifeFor(ctx, packageUrl)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNoEndOfLifeData) && !errors.Is(err, ports.ErrInvalidPackageURL) {
+			slog.WarnContext(ctx, "Failed to determine end-of-life",
+				slog.String("package_url", packageUrl),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		return nil
+	}
+
+	props := []cyclonedx.Property{
+		{Name: PropertyEOL, Value: strconv.FormatBool(cycle.IsEOL(at))},
+		{Name: PropertyEOLCycle, Value: cycle.Product + "@" + cycle.Cycle},
+	}
+
+	if !cycle.EOLDate.IsZero() {
+		props = append(props, cyclonedx.Property{Name: PropertyEOLDate, Value: cycle.EOLDate.Format(time.DateOnly)})
+	}
+
+	return props
+}
+
+func appendNonEmpty(props []cyclonedx.Property, name, value string) []cyclonedx.Property {
+	if value == "" {
+		return props
+	}
+
+	return append(props, cyclonedx.Property{Name: name, Value: value})
+}
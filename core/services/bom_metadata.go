@@ -0,0 +1,95 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+)
+
+const (
+	toolName   = "aucs"
+	toolVendor = "prskr"
+)
+
+const (
+	PropertyLatestVersion = "aucs:package:latest_version"
+	// PropertyLatestPreReleaseVersion is only set if the registry has a pre-release newer than the latest version
+	PropertyLatestPreReleaseVersion = "aucs:package:latest_prerelease_version"
+	// PropertyLatestAppVersion is the version of the application packaged by the latest version e.g. of Helm charts
+	PropertyLatestAppVersion = "aucs:package:latest_app_version"
+	// PropertyLatestRevision is the revision of the latest version e.g. the recipe revision of Conan packages
+	PropertyLatestRevision = "aucs:package:latest_revision"
+	PropertyLookupStatus   = "aucs:package:lookup_status"
+	PropertyLookupError    = "aucs:package:lookup_error"
+	PropertyEnrichedAt     = "aucs:enriched_at"
+	PropertyVersion        = "aucs:version"
+	PropertyRegistries     = "aucs:registries"
+)
+
+// recordMetadata adds aucs as tool to the BOM metadata and records when and against which registries the BOM was enriched.
+func (e BOMEnricher) recordMetadata(bom *cyclonedx.BOM, results []ComponentResult, enrichedAt time.Time, extra ...cyclonedx.Property) {
+	if bom.Metadata == nil {
+		bom.Metadata = new(cyclonedx.Metadata)
+	}
+
+	removeTool(bom.Metadata)
+	addTool(bom, e.Version)
+
+	replaceProperties(&bom.Metadata.Properties, append([]cyclonedx.Property{
+		{Name: PropertyEnrichedAt, Value: enrichedAt.UTC().Format(time.RFC3339)},
+		{Name: PropertyVersion, Value: e.Version},
+		{Name: PropertyRegistries, Value: strings.Join(consultedRegistries(results), ",")},
+	}, extra...)...)
+}
+
+// addTool uses the legacy tools list if the BOM already uses it or its spec version predates components as tools.
+func addTool(bom *cyclonedx.BOM, version string) {
+	if bom.Metadata.Tools == nil {
+		bom.Metadata.Tools = new(cyclonedx.ToolsChoice)
+	}
+
+	tools := bom.Metadata.Tools
+
+	if tools.Tools != nil || (tools.Components == nil && tools.Services == nil && bom.SpecVersion < cyclonedx.SpecVersion1_5) {
+		if tools.Tools == nil {
+			tools.Tools = new([]cyclonedx.Tool)
+		}
+
+		*tools.Tools = append(*tools.Tools, cyclonedx.Tool{Vendor: toolVendor, Name: toolName, Version: version})
+
+		return
+	}
+
+	if tools.Components == nil {
+		tools.Components = new([]cyclonedx.Component)
+	}
+
+	*tools.Components = append(*tools.Components, cyclonedx.Component{
+		Type:    cyclonedx.ComponentTypeApplication,
+		Group:   toolVendor,
+		Name:    toolName,
+		Version: version,
+	})
+}
+
+// consultedRegistries returns the sorted ecosystems for which a registry was queried - now or in a previous run for cached results.
+func consultedRegistries(results []ComponentResult) []string {
+	registries := make([]string, 0)
+
+	for _, r := range results {
+		switch r.Outcome {
+		case ComponentOutcomeUnsupported, ComponentOutcomeInvalid:
+			continue
+		}
+
+		if r.Ecosystem != "" && !slices.Contains(registries, r.Ecosystem) {
+			registries = append(registries, r.Ecosystem)
+		}
+	}
+
+	slices.Sort(registries)
+
+	return registries
+}
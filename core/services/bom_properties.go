@@ -0,0 +1,98 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+)
+
+// PropertyPrefix is shared by all properties aucs adds to a BOM.
+const PropertyPrefix = "aucs:"
+
+// StripBOM removes all data previously added by aucs from the BOM.
+func StripBOM(bom *cyclonedx.BOM) {
+	if bom.Components != nil {
+		for i := range *bom.Components {
+			removeBOMRef(&(*bom.Components)[i])
+			replaceProperties(&(*bom.Components)[i].Properties)
+		}
+	}
+
+	removeVulnerabilities(bom)
+
+	if bom.Metadata == nil {
+		return
+	}
+
+	replaceProperties(&bom.Metadata.Properties)
+	removeTool(bom.Metadata)
+
+	if isEmptyMetadata(bom.Metadata) {
+		bom.Metadata = nil
+	}
+}
+
+// replaceProperties removes all aucs properties and appends the given ones after all foreign properties.
+// Foreign properties keep their order so that repeated runs produce identical output.
+func replaceProperties(properties **[]cyclonedx.Property, aucsProperties ...cyclonedx.Property) {
+	var merged []cyclonedx.Property
+	if *properties != nil {
+		merged = slices.DeleteFunc(slices.Clone(**properties), func(p cyclonedx.Property) bool {
+			return strings.HasPrefix(p.Name, PropertyPrefix)
+		})
+	}
+
+	merged = append(merged, aucsProperties...)
+
+	if len(merged) == 0 {
+		*properties = nil
+		return
+	}
+
+	*properties = &merged
+}
+
+// appendProperties appends the given properties without touching existing ones.
+func appendProperties(properties **[]cyclonedx.Property, aucsProperties ...cyclonedx.Property) {
+	if *properties == nil {
+		*properties = new([]cyclonedx.Property)
+	}
+
+	**properties = append(**properties, aucsProperties...)
+}
+
+func removeTool(metadata *cyclonedx.Metadata) {
+	tools := metadata.Tools
+	if tools == nil {
+		return
+	}
+
+	if tools.Tools != nil {
+		*tools.Tools = slices.DeleteFunc(*tools.Tools, func(t cyclonedx.Tool) bool {
+			return t.Name == toolName && t.Vendor == toolVendor
+		})
+
+		if len(*tools.Tools) == 0 {
+			tools.Tools = nil
+		}
+	}
+
+	if tools.Components != nil {
+		*tools.Components = slices.DeleteFunc(*tools.Components, func(c cyclonedx.Component) bool {
+			return c.Name == toolName && c.Group == toolVendor
+		})
+
+		if len(*tools.Components) == 0 {
+			tools.Components = nil
+		}
+	}
+
+	if tools.Tools == nil && tools.Components == nil && tools.Services == nil {
+		metadata.Tools = nil
+	}
+}
+
+func isEmptyMetadata(metadata *cyclonedx.Metadata) bool {
+	return *metadata == cyclonedx.Metadata{}
+}
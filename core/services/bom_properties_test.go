@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/services"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestBOMEnricher_Enrich_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	enricher := services.BOMEnricher{
+		Lookup:      fakeLookup{"pkg:npm/is-even-ai@1.0.1": {LatestVersion: "1.0.5"}},
+		Parallelism: 1,
+		Version:     "v1.2.3",
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.SpecVersion = cyclonedx.SpecVersion1_4
+	bom.Components = &[]cyclonedx.Component{
+		{
+			PackageURL: "pkg:npm/is-even-ai@1.0.1",
+			Properties: &[]cyclonedx.Property{
+				{Name: "aucs:package:latest_version", Value: "1.0.4"},
+				{Name: "internal:team", Value: "platform"},
+			},
+		},
+	}
+
+	for range 2 {
+		_, err := enricher.Enrich(testx.Context(t), bom)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []cyclonedx.Property{
+		{Name: "internal:team", Value: "platform"},
+		{Name: services.PropertyLatestVersion, Value: "1.0.5"},
+		{Name: services.PropertyLookupStatus, Value: "enriched"},
+	}, *(*bom.Components)[0].Properties)
+
+	if assert.NotNil(t, bom.Metadata.Tools.Tools) {
+		assert.Len(t, *bom.Metadata.Tools.Tools, 1)
+	}
+
+	assert.Len(t, *bom.Metadata.Properties, 3)
+
+	services.StripBOM(bom)
+
+	assert.Equal(t, []cyclonedx.Property{{Name: "internal:team", Value: "platform"}}, *(*bom.Components)[0].Properties)
+	assert.Nil(t, bom.Metadata)
+}
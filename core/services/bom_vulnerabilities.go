@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const (
+	PropertyFirstNonVulnerableVersion = "aucs:package:first_non_vulnerable_version"
+	PropertyVulnerabilityStatus       = "aucs:vulnerabilities:status"
+	// PropertyVulnerability identifies a known vulnerability of a package in BOM formats without vulnerability records e.g. SPDX
+	PropertyVulnerability = "aucs:package:vulnerability"
+	// PropertyBOMRef records the bom-ref assigned by aucs to a component without one to be able to remove it again
+	PropertyBOMRef = "aucs:bom_ref"
+)
+
+const (
+	vulnerabilityStatusChecked = "checked"
+	vulnerabilityStatusFailed  = "failed"
+
+	// vulnerabilityRefPrefix identifies vulnerabilities added by aucs to replace them on re-enrichment
+	vulnerabilityRefPrefix = "aucs:vulnerability:"
+)
+
+// enrichVulnerabilities adds all known vulnerabilities of the components' current versions to the BOM.
+// Failing to query the source is not fatal but recorded in the BOM metadata.
+func (e BOMEnricher) enrichVulnerabilities(ctx context.Context, bom *cyclonedx.BOM) (status string, err error) {
+	ctx, span := tracer.Start(ctx, "BOMEnricher.EnrichVulnerabilities")
+	defer span.End()
+
+	if bom.Components == nil || len(*bom.Components) == 0 {
+		return vulnerabilityStatusChecked, nil
+	}
+
+	components := *bom.Components
+	packageUrls := make([]string, 0, len(components))
+
+	for _, c := range components {
+		if c.PackageURL != "" {
+			packageUrls = append(packageUrls, c.PackageURL)
+		}
+	}
+
+	reports, err := e.Vulnerabilities.VulnerabilitiesFor(ctx, packageUrls)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return vulnerabilityStatusFailed, ctxErr
+		}
+
+		slog.WarnContext(ctx, "Failed to determine vulnerabilities", slog.String("err", err.Error()))
+
+		return vulnerabilityStatusFailed, nil
+	}
+
+	var (
+		vulnsByID = make(map[string]*cyclonedx.Vulnerability)
+		usedRefs  = make(map[string]bool, len(components))
+	)
+
+	for _, c := range components {
+		if c.BOMRef != "" {
+			usedRefs[c.BOMRef] = true
+		}
+	}
+
+	for i := range components {
+		component := &components[i]
+
+		report, ok := reports[component.PackageURL]
+		if !ok || len(report.Vulnerabilities) == 0 {
+			continue
+		}
+
+		ensureBOMRef(component, i, usedRefs)
+
+		for _, v := range report.Vulnerabilities {
+			vuln, ok := vulnsByID[v.ID]
+			if !ok {
+				vuln = vulnerabilityFor(v)
+				vulnsByID[v.ID] = vuln
+			}
+
+			*vuln.Affects = append(*vuln.Affects, cyclonedx.Affects{
+				Ref: component.BOMRef,
+				Range: &[]cyclonedx.AffectedVersions{
+					{Version: component.Version, Status: cyclonedx.VulnerabilityStatusAffected},
+				},
+			})
+		}
+
+		if report.FirstNonVulnerableVersion != "" {
+			appendProperties(&component.Properties, cyclonedx.Property{
+				Name:  PropertyFirstNonVulnerableVersion,
+				Value: report.FirstNonVulnerableVersion,
+			})
+		}
+	}
+
+	ids := make([]string, 0, len(vulnsByID))
+	for id := range vulnsByID {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	if len(ids) > 0 && bom.Vulnerabilities == nil {
+		bom.Vulnerabilities = new([]cyclonedx.Vulnerability)
+	}
+
+	for _, id := range ids {
+		*bom.Vulnerabilities = append(*bom.Vulnerabilities, *vulnsByID[id])
+	}
+
+	return vulnerabilityStatusChecked, nil
+}
+
+func vulnerabilityFor(v ports.Vulnerability) *cyclonedx.Vulnerability {
+	vuln := &cyclonedx.Vulnerability{
+		BOMRef:      vulnerabilityRefPrefix + v.ID,
+		ID:          v.ID,
+		Source:      &cyclonedx.Source{Name: v.Source, URL: v.URL},
+		Description: v.Summary,
+		Detail:      v.Details,
+		Affects:     new([]cyclonedx.Affects),
+	}
+
+	if !v.Published.IsZero() {
+		vuln.Published = v.Published.UTC().Format(time.RFC3339)
+	}
+
+	if !v.Modified.IsZero() {
+		vuln.Updated = v.Modified.UTC().Format(time.RFC3339)
+	}
+
+	if len(v.Aliases) > 0 {
+		references := make([]cyclonedx.VulnerabilityReference, 0, len(v.Aliases))
+		for _, alias := range v.Aliases {
+			references = append(references, cyclonedx.VulnerabilityReference{ID: alias})
+		}
+
+		vuln.References = &references
+	}
+
+	if len(v.References) > 0 {
+		advisories := make([]cyclonedx.Advisory, 0, len(v.References))
+		for _, ref := range v.References {
+			advisories = append(advisories, cyclonedx.Advisory{URL: ref})
+		}
+
+		vuln.Advisories = &advisories
+	}
+
+	if len(v.Severities) > 0 {
+		ratings := make([]cyclonedx.VulnerabilityRating, 0, len(v.Severities))
+		for _, s := range v.Severities {
+			ratings = append(ratings, cyclonedx.VulnerabilityRating{Method: scoringMethodFor(s), Vector: s.Score})
+		}
+
+		vuln.Ratings = &ratings
+	}
+
+	if len(v.FixedVersions) > 0 {
+		vuln.Recommendation = "Upgrade to one of the fixed versions: " + strings.Join(v.FixedVersions, ", ")
+	}
+
+	return vuln
+}
+
+func scoringMethodFor(s ports.VulnerabilitySeverity) cyclonedx.ScoringMethod {
+	switch {
+	case s.Type == "CVSS_V2":
+		return cyclonedx.ScoringMethodCVSSv2
+	case s.Type == "CVSS_V4":
+		return cyclonedx.ScoringMethodCVSSv4
+	case strings.HasPrefix(s.Score, "CVSS:3.1/"):
+		return cyclonedx.ScoringMethodCVSSv31
+	case s.Type == "CVSS_V3":
+		return cyclonedx.ScoringMethodCVSSv3
+	default:
+		return cyclonedx.ScoringMethodOther
+	}
+}
+
+// ensureBOMRef assigns the package URL as bom-ref to components without one to be able to reference them.
+// Assigned bom-refs are recorded as property to remove them with removeBOMRef.
+func ensureBOMRef(component *cyclonedx.Component, idx int, usedRefs map[string]bool) {
+	if component.BOMRef != "" {
+		return
+	}
+
+	ref := component.PackageURL
+	if usedRefs[ref] {
+		ref = ref + "#" + strconv.Itoa(idx)
+	}
+
+	component.BOMRef = ref
+	usedRefs[ref] = true
+
+	appendProperties(&component.Properties, cyclonedx.Property{Name: PropertyBOMRef, Value: ref})
+}
+
+// removeBOMRef removes the bom-ref of the component if it was assigned by ensureBOMRef.
+func removeBOMRef(component *cyclonedx.Component) {
+	if component.BOMRef == "" || component.Properties == nil {
+		return
+	}
+
+	if slices.Contains(*component.Properties, cyclonedx.Property{Name: PropertyBOMRef, Value: component.BOMRef}) {
+		component.BOMRef = ""
+	}
+}
+
+// removeVulnerabilities removes all vulnerabilities previously added by aucs.
+func removeVulnerabilities(bom *cyclonedx.BOM) {
+	if bom.Vulnerabilities == nil {
+		return
+	}
+
+	*bom.Vulnerabilities = slices.DeleteFunc(*bom.Vulnerabilities, func(v cyclonedx.Vulnerability) bool {
+		return strings.HasPrefix(v.BOMRef, vulnerabilityRefPrefix)
+	})
+
+	if len(*bom.Vulnerabilities) == 0 {
+		bom.Vulnerabilities = nil
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+// ComponentOutcome classifies how a single component was processed during enrichment.
+type ComponentOutcome string
+
+const (
+	ComponentOutcomeEnriched    ComponentOutcome = "enriched"
+	ComponentOutcomeCached      ComponentOutcome = "cached"
+	ComponentOutcomeUnsupported ComponentOutcome = "unsupported"
+	ComponentOutcomeNotFound    ComponentOutcome = "not_found"
+	ComponentOutcomeAmbiguous   ComponentOutcome = "ambiguous"
+	ComponentOutcomeInvalid     ComponentOutcome = "invalid"
+	ComponentOutcomeError       ComponentOutcome = "error"
+	ComponentOutcomeCanceled    ComponentOutcome = "canceled"
+	// ComponentOutcomeSkipped is reported for components without package URL e.g. files or operating systems
+	ComponentOutcomeSkipped ComponentOutcome = "skipped"
+)
+
+// ComponentOutcomes lists all outcomes in the order they're reported.
+var ComponentOutcomes = []ComponentOutcome{
+	ComponentOutcomeEnriched,
+	ComponentOutcomeCached,
+	ComponentOutcomeUnsupported,
+	ComponentOutcomeNotFound,
+	ComponentOutcomeAmbiguous,
+	ComponentOutcomeInvalid,
+	ComponentOutcomeError,
+	ComponentOutcomeCanceled,
+	ComponentOutcomeSkipped,
+}
+
+// IsFailure reports whether the outcome counts as failed lookup.
+// Unsupported package types and components without package URL are expected in most SBOMs and are not considered failures.
+func (o ComponentOutcome) IsFailure() bool {
+	switch o {
+	case ComponentOutcomeEnriched, ComponentOutcomeCached, ComponentOutcomeUnsupported, ComponentOutcomeSkipped:
+		return false
+	default:
+		return true
+	}
+}
+
+func ComponentOutcomeFor(info *ports.PackageInfo, err error) ComponentOutcome {
+	switch {
+	case err == nil && info.CacheStatus == ports.CacheStatusHit, err == nil && info.CacheStatus == ports.CacheStatusStale:
+		return ComponentOutcomeCached
+	case err == nil:
+		return ComponentOutcomeEnriched
+	case errors.Is(err, ports.ErrNoCheckerForPackageType):
+		return ComponentOutcomeUnsupported
+	case errors.Is(err, ports.ErrNoMatchingPackageFound):
+		return ComponentOutcomeNotFound
+	case errors.Is(err, ports.ErrAmbiguousPackageFound):
+		return ComponentOutcomeAmbiguous
+	case errors.Is(err, ports.ErrInvalidPackageURL):
+		return ComponentOutcomeInvalid
+	default:
+		return ComponentOutcomeError
+	}
+}
+
+// ComponentResult is the outcome of a single component.
+type ComponentResult struct {
+	PackageURL string           `json:"package_url"`
+	Ecosystem  string           `json:"ecosystem,omitempty"`
+	Outcome    ComponentOutcome `json:"outcome"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// EnrichSummary aggregates the outcomes of all components of a BOM.
+type EnrichSummary struct {
+	Components int                      `json:"components"`
+	Outcomes   map[ComponentOutcome]int `json:"outcomes"`
+	Failures   []ComponentResult        `json:"failures"`
+	Duration   time.Duration            `json:"duration_ns"`
+}
+
+func newEnrichSummary(results []ComponentResult, duration time.Duration) EnrichSummary {
+	summary := EnrichSummary{
+		Components: len(results),
+		Outcomes:   make(map[ComponentOutcome]int, len(ComponentOutcomes)),
+		Failures:   make([]ComponentResult, 0),
+		Duration:   duration,
+	}
+
+	for _, o := range ComponentOutcomes {
+		summary.Outcomes[o] = 0
+	}
+
+	for _, r := range results {
+		summary.Outcomes[r.Outcome]++
+		if r.Outcome.IsFailure() {
+			summary.Failures = append(summary.Failures, r)
+		}
+	}
+
+	return summary
+}
+
+// ErrorRatio is the share of failed lookups among all components with package URL of a supported package type.
+func (s EnrichSummary) ErrorRatio() float64 {
+	checked := s.Components - s.Outcomes[ComponentOutcomeUnsupported] - s.Outcomes[ComponentOutcomeSkipped]
+	if checked <= 0 {
+		return 0
+	}
+
+	return float64(len(s.Failures)) / float64(checked)
+}
@@ -0,0 +1,355 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/core/services"
+)
+
+const (
+	defaultMaxBatchSize = 1000
+	maxRequestBodySize  = 32 << 20
+)
+
+var tracer = otel.Tracer("github.com/prskr/aucs/handlers/api")
+
+var (
+	ErrBatchTooLarge      = errors.New("batch too large")
+	ErrUnsupportedBOMType = errors.New("unsupported BOM content type")
+)
+
+func NewServer(lookup ports.PackageLookup, parallelism uint8) *Server {
+	return &Server{
+		Lookup:       lookup,
+		Parallelism:  parallelism,
+		MaxBatchSize: defaultMaxBatchSize,
+	}
+}
+
+// Server exposes package lookups and BOM enrichment as REST API.
+type Server struct {
+	Lookup       ports.PackageLookup
+	Parallelism  uint8
+	MaxBatchSize int
+	// MetricsHandler is served at /metrics if set
+	MetricsHandler http.Handler
+	// Version of aucs recorded in enriched BOMs
+	Version string
+	// Vulnerabilities is optional - if set enriched BOMs contain known vulnerabilities
+	Vulnerabilities ports.VulnerabilitySource
+	// EndOfLife is optional - if set enriched BOMs contain the end-of-life status of the components
+	EndOfLife ports.EndOfLifeSource
+
+	ready atomic.Bool
+}
+
+// SetReady toggles the readiness probe e.g. to drain traffic before shutting down.
+func (s *Server) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	mux.HandleFunc("GET /v1/packages", s.handleLookup)
+	mux.HandleFunc("POST /v1/packages/lookup", s.handleBatchLookup)
+	mux.HandleFunc("POST /v1/sbom", s.handleEnrichBOM)
+
+	if s.MetricsHandler != nil {
+		mux.Handle("GET /metrics", s.MetricsHandler)
+	}
+
+	return mux
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ready"))
+}
+
+func (s *Server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	purl := r.URL.Query().Get("purl")
+	if purl == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing purl query parameter"))
+		return
+	}
+
+	result, status := s.lookup(r, purl)
+	writeJSON(w, status, result)
+}
+
+func (s *Server) handleBatchLookup(w http.ResponseWriter, r *http.Request) {
+	var req batchLookupRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode request: %w", err))
+		return
+	}
+
+	if len(req.PackageURLs) > s.MaxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: at most %d package URLs allowed", ErrBatchTooLarge, s.MaxBatchSize))
+		return
+	}
+
+	var (
+		resp      = batchLookupResponse{Results: make([]lookupResult, len(req.PackageURLs))}
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, max(s.Parallelism, 1))
+	)
+
+	for i, purl := range req.PackageURLs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			resp.Results[i], _ = s.lookup(r, purl)
+		}()
+	}
+
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleEnrichBOM(w http.ResponseWriter, r *http.Request) {
+	if isSPDX(r.Header.Get("Content-Type")) {
+		s.handleEnrichSPDX(w, r)
+		return
+	}
+
+	format, contentType, err := bomFormatFor(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	_, decodeSpan := tracer.Start(r.Context(), "DecodeBOM")
+
+	bom := cyclonedx.NewBOM()
+	if err := cyclonedx.NewBOMDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize), format).Decode(bom); err != nil {
+		decodeSpan.SetStatus(codes.Error, err.Error())
+		decodeSpan.End()
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode BOM: %w", err))
+		return
+	}
+
+	decodeSpan.End()
+
+	if _, err := s.enricher().Enrich(r.Context(), bom); err != nil {
+		writeEnrichError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := cyclonedx.NewBOMEncoder(w, format).Encode(bom); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write enriched BOM", slog.String("err", err.Error()))
+	}
+}
+
+// handleEnrichSPDX enriches the packages of SPDX JSON documents with package URL - the results are recorded as
+// annotations of the packages.
+func (s *Server) handleEnrichSPDX(w http.ResponseWriter, r *http.Request) {
+	_, decodeSpan := tracer.Start(r.Context(), "DecodeSPDX")
+
+	doc, rawPackages, packages, err := decodeSPDX(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		decodeSpan.SetStatus(codes.Error, err.Error())
+		decodeSpan.End()
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode SPDX document: %w", err))
+		return
+	}
+
+	decodeSpan.End()
+
+	bom := toBOM(packages)
+	if _, err := s.enricher().Enrich(r.Context(), bom); err != nil {
+		writeEnrichError(w, err)
+		return
+	}
+
+	if err := applyBOM(doc, rawPackages, packages, bom, s.Version, time.Now()); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to annotate SPDX document: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", spdxMediaType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		slog.WarnContext(r.Context(), "Failed to write enriched SPDX document", slog.String("err", err.Error()))
+	}
+}
+
+func (s *Server) enricher() services.BOMEnricher {
+	return services.BOMEnricher{
+		Lookup:          s.Lookup,
+		Parallelism:     max(s.Parallelism, 1),
+		Version:         s.Version,
+		Vulnerabilities: s.Vulnerabilities,
+		EndOfLife:       s.EndOfLife,
+	}
+}
+
+func writeEnrichError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+
+	writeError(w, status, err)
+}
+
+func (s *Server) lookup(r *http.Request, purl string) (lookupResult, int) {
+	result := lookupResult{PackageURL: purl}
+
+	info, err := s.Lookup.LatestVersionFor(r.Context(), purl)
+	if err != nil {
+		result.Error = err.Error()
+		return result, statusForLookupError(err)
+	}
+
+	result.Info = &packageInfo{
+		Namespace:               info.Namespace,
+		Name:                    info.Name,
+		CurrentVersion:          info.CurrentVersion,
+		LatestVersion:           info.LatestVersion,
+		LatestPreReleaseVersion: info.LatestPreReleaseVersion,
+		LatestAppVersion:        info.LatestAppVersion,
+		LatestRevision:          info.LatestRevision,
+		PackageManager:          info.PackageManager,
+		Deprecated:              info.Deprecated,
+		DeprecationMessage:      info.DeprecationMessage,
+		Replacement:             info.Replacement,
+		Yanked:                  info.Yanked,
+		YankedReason:            info.YankedReason,
+		CurrentLicense:          info.CurrentLicense,
+		LatestLicense:           info.LatestLicense,
+	}
+
+	return result, http.StatusOK
+}
+
+func statusForLookupError(err error) int {
+	switch {
+	case errors.Is(err, ports.ErrInvalidPackageURL):
+		return http.StatusBadRequest
+	case errors.Is(err, ports.ErrNoCheckerForPackageType):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ports.ErrNoMatchingPackageFound):
+		return http.StatusNotFound
+	case errors.Is(err, ports.ErrAmbiguousPackageFound):
+		return http.StatusConflict
+	case errors.Is(err, ports.ErrCurrentVersionGreaterThanLatest):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func isSPDX(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == spdxMediaType
+}
+
+// bomFormatFor determines the CycloneDX format from the request content type - JSON is the default.
+func bomFormatFor(contentType string) (format cyclonedx.BOMFileFormat, responseContentType string, err error) {
+	if contentType == "" {
+		return cyclonedx.BOMFileFormatJSON, "application/vnd.cyclonedx+json", nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %w", ErrUnsupportedBOMType, err)
+	}
+
+	switch mediaType {
+	case "application/vnd.cyclonedx+json", "application/json":
+		return cyclonedx.BOMFileFormatJSON, "application/vnd.cyclonedx+json", nil
+	case "application/vnd.cyclonedx+xml", "application/xml", "text/xml":
+		return cyclonedx.BOMFileFormatXML, "application/vnd.cyclonedx+xml", nil
+	default:
+		return 0, "", fmt.Errorf("%w: %s - only CycloneDX JSON/XML and SPDX JSON are supported", ErrUnsupportedBOMType, mediaType)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Warn("Failed to write response", slog.String("err", err.Error()))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type batchLookupRequest struct {
+	PackageURLs []string `json:"purls"`
+}
+
+type batchLookupResponse struct {
+	Results []lookupResult `json:"results"`
+}
+
+type lookupResult struct {
+	PackageURL string       `json:"purl"`
+	Info       *packageInfo `json:"info,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+type packageInfo struct {
+	Namespace               string `json:"namespace,omitempty"`
+	Name                    string `json:"name"`
+	CurrentVersion          string `json:"currentVersion,omitempty"`
+	LatestVersion           string `json:"latestVersion"`
+	LatestPreReleaseVersion string `json:"latestPreReleaseVersion,omitempty"`
+	LatestAppVersion        string `json:"latestAppVersion,omitempty"`
+	LatestRevision          string `json:"latestRevision,omitempty"`
+	PackageManager          string `json:"packageManager,omitempty"`
+
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+	Replacement        string `json:"replacement,omitempty"`
+	Yanked             bool   `json:"yanked,omitempty"`
+	YankedReason       string `json:"yankedReason,omitempty"`
+	CurrentLicense     string `json:"currentLicense,omitempty"`
+	LatestLicense      string `json:"latestLicense,omitempty"`
+}
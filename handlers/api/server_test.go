@@ -0,0 +1,155 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/handlers/api"
+)
+
+var _ ports.PackageLookup = (fakeLookup)(nil)
+
+type fakeLookup map[string]*ports.PackageInfo
+
+func (f fakeLookup) LatestVersionFor(_ context.Context, packageUrl string) (*ports.PackageInfo, error) {
+	info, ok := f[packageUrl]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoMatchingPackageFound, packageUrl)
+	}
+
+	return info, nil
+}
+
+func TestServer_Handler(t *testing.T) {
+	t.Parallel()
+
+	lookup := fakeLookup{
+		"pkg:npm/is-even-ai@1.0.1": {Name: "is-even-ai", CurrentVersion: "1.0.1", LatestVersion: "1.0.5", PackageManager: "npm"},
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    []string
+		// unwantedBody must not be part of the response
+		unwantedBody []string
+	}{
+		{
+			name:       "Health",
+			method:     http.MethodGet,
+			target:     "/healthz",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Single lookup",
+			method:     http.MethodGet,
+			target:     "/v1/packages?purl=pkg:npm/is-even-ai@1.0.1",
+			wantStatus: http.StatusOK,
+			wantBody:   []string{`"latestVersion":"1.0.5"`},
+		},
+		{
+			name:       "Single lookup - unknown package",
+			method:     http.MethodGet,
+			target:     "/v1/packages?purl=pkg:npm/is-odd-ai@1.0.0",
+			wantStatus: http.StatusNotFound,
+			wantBody:   []string{`"error":`},
+		},
+		{
+			name:       "Batch lookup",
+			method:     http.MethodPost,
+			target:     "/v1/packages/lookup",
+			body:       `{"purls": ["pkg:npm/is-even-ai@1.0.1", "pkg:npm/is-odd-ai@1.0.0"]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   []string{`"latestVersion":"1.0.5"`, `"purl":"pkg:npm/is-odd-ai@1.0.0","error":`},
+		},
+		{
+			name:        "Enrich BOM",
+			method:      http.MethodPost,
+			target:      "/v1/sbom",
+			contentType: "application/vnd.cyclonedx+json",
+			body:        `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[{"type":"library","name":"is-even-ai","version":"1.0.1","purl":"pkg:npm/is-even-ai@1.0.1"}]}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    []string{`"name":"aucs:package:latest_version","value":"1.0.5"`},
+		},
+		{
+			name:        "Enrich SPDX document",
+			method:      http.MethodPost,
+			target:      "/v1/sbom",
+			contentType: "application/spdx+json",
+			body: `{"spdxVersion":"SPDX-2.3","SPDXID":"SPDXRef-DOCUMENT","packages":[
+				{"SPDXID":"SPDXRef-is-even-ai","name":"is-even-ai","versionInfo":"1.0.1","externalRefs":[{"referenceCategory":"PACKAGE-MANAGER","referenceType":"purl","referenceLocator":"pkg:npm/is-even-ai@1.0.1"}],
+				 "annotations":[{"annotationDate":"2024-01-01T00:00:00Z","annotationType":"REVIEW","annotator":"Person: Jane","comment":"reviewed"},{"annotationDate":"2024-01-01T00:00:00Z","annotationType":"OTHER","annotator":"Tool: aucs","comment":"aucs:package:latest_version=1.0.2"}]},
+				{"SPDXID":"SPDXRef-no-purl","name":"vendored","versionInfo":"1.0.0"}
+			]}`,
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				`"spdxVersion":"SPDX-2.3"`,
+				`"comment":"reviewed"`,
+				`"comment":"aucs:package:latest_version=1.0.5"`,
+				`"comment":"aucs:package:lookup_status=`,
+				`"comment":"aucs:enriched_at=`,
+				`{"SPDXID":"SPDXRef-no-purl","name":"vendored","versionInfo":"1.0.0"}`,
+			},
+			unwantedBody: []string{`latest_version=1.0.2`},
+		},
+		{
+			name:        "Enrich BOM - unsupported content type",
+			method:      http.MethodPost,
+			target:      "/v1/sbom",
+			contentType: "text/plain",
+			body:        `{}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := api.NewServer(lookup, 2)
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			for _, want := range tt.wantBody {
+				assert.Contains(t, rec.Body.String(), want)
+			}
+
+			for _, unwanted := range tt.unwantedBody {
+				assert.NotContains(t, rec.Body.String(), unwanted)
+			}
+		})
+	}
+}
+
+func TestServer_Readiness(t *testing.T) {
+	t.Parallel()
+
+	srv := api.NewServer(fakeLookup{}, 1)
+	handler := srv.Handler()
+
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, probe())
+	srv.SetReady(true)
+	assert.Equal(t, http.StatusOK, probe())
+}
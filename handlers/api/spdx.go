@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/prskr/aucs/core/services"
+)
+
+const (
+	spdxMediaType = "application/spdx+json"
+
+	// spdxPurlReferenceType identifies the package URL among the external references of a package
+	spdxPurlReferenceType = "purl"
+	spdxAnnotationType    = "OTHER"
+)
+
+// spdxDocument is an SPDX 2.x JSON document - fields aucs doesn't modify are kept as they are.
+type spdxDocument map[string]json.RawMessage
+
+// spdxPackage is the subset of a package required to look it up.
+type spdxPackage struct {
+	SPDXID       string            `json:"SPDXID"`
+	Name         string            `json:"name"`
+	VersionInfo  string            `json:"versionInfo"`
+	ExternalRefs []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceType    string `json:"referenceType"`
+	ReferenceLocator string `json:"referenceLocator"`
+}
+
+type spdxAnnotation struct {
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Annotator      string `json:"annotator"`
+	Comment        string `json:"comment"`
+}
+
+// decodeSPDX returns the packages as they are to modify them and in their parsed form - both in document order.
+func decodeSPDX(r io.Reader) (doc spdxDocument, rawPackages []spdxDocument, packages []spdxPackage, err error) {
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if raw, ok := doc["packages"]; ok {
+		if err := json.Unmarshal(raw, &rawPackages); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to decode packages: %w", err)
+		}
+
+		if err := json.Unmarshal(raw, &packages); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to decode packages: %w", err)
+		}
+	}
+
+	return doc, rawPackages, packages, nil
+}
+
+// toBOM converts the packages with package URL to components referenced by their SPDX identifier to enrich them.
+func toBOM(packages []spdxPackage) *cyclonedx.BOM {
+	components := make([]cyclonedx.Component, 0, len(packages))
+
+	for _, pkg := range packages {
+		idx := slices.IndexFunc(pkg.ExternalRefs, func(ref spdxExternalRef) bool {
+			return ref.ReferenceType == spdxPurlReferenceType
+		})
+		if idx < 0 || pkg.SPDXID == "" {
+			continue
+		}
+
+		components = append(components, cyclonedx.Component{
+			BOMRef:     pkg.SPDXID,
+			Type:       cyclonedx.ComponentTypeLibrary,
+			Name:       pkg.Name,
+			Version:    pkg.VersionInfo,
+			PackageURL: pkg.ExternalRefs[idx].ReferenceLocator,
+		})
+	}
+
+	bom := cyclonedx.NewBOM()
+	bom.Components = &components
+
+	return bom
+}
+
+// applyBOM records the properties of the enriched components and known vulnerabilities as annotations of the
+// packages and the metadata properties as annotations of the document - annotations of previous runs are replaced.
+func applyBOM(doc spdxDocument, rawPackages []spdxDocument, packages []spdxPackage, bom *cyclonedx.BOM, version string, now time.Time) error {
+	annotator := "Tool: aucs"
+	if version != "" {
+		annotator += "-" + version
+	}
+
+	annotationsFor := func(properties *[]cyclonedx.Property) []spdxAnnotation {
+		if properties == nil {
+			return nil
+		}
+
+		annotations := make([]spdxAnnotation, 0, len(*properties))
+		for _, p := range *properties {
+			annotations = append(annotations, spdxAnnotation{
+				AnnotationDate: now.UTC().Format(time.RFC3339),
+				AnnotationType: spdxAnnotationType,
+				Annotator:      annotator,
+				Comment:        p.Name + "=" + p.Value,
+			})
+		}
+
+		return annotations
+	}
+
+	annotationsByID := make(map[string][]spdxAnnotation)
+	if bom.Components != nil {
+		for i := range *bom.Components {
+			component := &(*bom.Components)[i]
+			annotationsByID[component.BOMRef] = annotationsFor(component.Properties)
+		}
+	}
+
+	if bom.Vulnerabilities != nil {
+		for _, vuln := range *bom.Vulnerabilities {
+			if vuln.Affects == nil {
+				continue
+			}
+
+			for _, affects := range *vuln.Affects {
+				annotationsByID[affects.Ref] = append(annotationsByID[affects.Ref], annotationsFor(&[]cyclonedx.Property{
+					{Name: services.PropertyVulnerability, Value: vuln.ID},
+				})...)
+			}
+		}
+	}
+
+	for i, pkg := range packages {
+		if annotations, ok := annotationsByID[pkg.SPDXID]; ok {
+			if err := replaceAnnotations(rawPackages[i], annotations); err != nil {
+				return err
+			}
+		}
+	}
+
+	var metadataProperties *[]cyclonedx.Property
+	if bom.Metadata != nil {
+		metadataProperties = bom.Metadata.Properties
+	}
+
+	if err := replaceAnnotations(doc, annotationsFor(metadataProperties)); err != nil {
+		return err
+	}
+
+	if rawPackages == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(rawPackages)
+	if err != nil {
+		return err
+	}
+
+	doc["packages"] = raw
+
+	return nil
+}
+
+// replaceAnnotations removes all annotations added by aucs and appends the given ones after all foreign annotations.
+func replaceAnnotations(element spdxDocument, annotations []spdxAnnotation) error {
+	var existing []json.RawMessage
+	if raw, ok := element["annotations"]; ok {
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return fmt.Errorf("failed to decode annotations: %w", err)
+		}
+	}
+
+	merged := slices.DeleteFunc(existing, func(raw json.RawMessage) bool {
+		var annotation spdxAnnotation
+		return json.Unmarshal(raw, &annotation) == nil && strings.HasPrefix(annotation.Comment, services.PropertyPrefix)
+	})
+
+	for _, annotation := range annotations {
+		raw, err := json.Marshal(annotation)
+		if err != nil {
+			return err
+		}
+
+		merged = append(merged, raw)
+	}
+
+	if len(merged) == 0 {
+		delete(element, "annotations")
+		return nil
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	element["annotations"] = raw
+
+	return nil
+}
@@ -0,0 +1,370 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/infrastructure/vulnerability"
+)
+
+type CacheCliHandler struct {
+	DB DBFlag `embed:"" prefix:"db."`
+
+	Stats      CacheStatsCliHandler      `cmd:"" help:"Show number and size of cached lookups per ecosystem"`
+	List       CacheListCliHandler       `cmd:"" help:"List cached lookups with their age"`
+	Purge      CachePurgeCliHandler      `cmd:"" help:"Delete all cache entries or all lookups and vulnerability reports of the given ecosystems"`
+	Invalidate CacheInvalidateCliHandler `cmd:"" help:"Delete cached lookups and vulnerability reports matching package URL glob patterns"`
+	Export     CacheExportCliHandler     `cmd:"" help:"Export cache entries to a portable bundle"`
+	Import     CacheImportCliHandler     `cmd:"" help:"Import cache entries from a bundle"`
+}
+
+func (h *CacheCliHandler) AfterApply(kongCtx *kong.Context) error {
+	// the cache commands operate on the persisted entries - buffering them in memory would silently discard changes
+	dbFlag := h.DB
+	dbFlag.LockFallback = "fail"
+
+	kv, err := dbFlag.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	kongCtx.BindTo(kv, (*ports.KeyValueStore)(nil))
+
+	return nil
+}
+
+type CacheStatsCliHandler struct{}
+
+func (h *CacheStatsCliHandler) Run(ctx context.Context, kv ports.KeyValueStore, stdout ports.STDOUT) (err error) {
+	defer func() {
+		err = errors.Join(err, kv.Close())
+	}()
+
+	type ecosystemStats struct {
+		Entries int
+		Size    int
+	}
+
+	var (
+		statsByEcosystem = make(map[string]*ecosystemStats)
+		total            ecosystemStats
+	)
+
+	err = kv.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		if vulnerability.IsCacheKey(entry.Key) {
+			return nil
+		}
+
+		ecosystem := "unknown"
+		if purl, err := checker.PackageURLFromCacheKey(entry.Key); err == nil {
+			ecosystem = purl.Type
+		}
+
+		stats, ok := statsByEcosystem[ecosystem]
+		if !ok {
+			stats = new(ecosystemStats)
+			statsByEcosystem[ecosystem] = stats
+		}
+
+		size := len(entry.Key) + len(entry.Value)
+		stats.Entries++
+		stats.Size += size
+		total.Entries++
+		total.Size += size
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to iterate cache entries: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ECOSYSTEM\tENTRIES\tSIZE")
+
+	for _, ecosystem := range slices.Sorted(maps.Keys(statsByEcosystem)) {
+		stats := statsByEcosystem[ecosystem]
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\n", ecosystem, stats.Entries, formatBytes(stats.Size))
+	}
+
+	_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\n", "TOTAL", total.Entries, formatBytes(total.Size))
+
+	return tw.Flush()
+}
+
+type CacheListCliHandler struct {
+	Ecosystems []string `name:"ecosystem" help:"Only list entries of the given ecosystems"`
+}
+
+func (h *CacheListCliHandler) Run(ctx context.Context, kv ports.KeyValueStore, stdout ports.STDOUT) (err error) {
+	defer func() {
+		err = errors.Join(err, kv.Close())
+	}()
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PACKAGE\tAGE\tEXPIRES IN")
+
+	err = iterateEcosystems(ctx, kv, h.Ecosystems, func(entry ports.KeyValueEntry) error {
+		if vulnerability.IsCacheKey(entry.Key) {
+			return nil
+		}
+
+		purl, err := checker.PackageURLFromCacheKey(entry.Key)
+		if err != nil {
+			return err
+		}
+
+		display := displayPackageURL(purl)
+		if purl.Version != "" {
+			display += "@" + purl.Version
+		}
+
+		if len(purl.Qualifiers) > 0 {
+			display += "?" + purl.Qualifiers.String()
+		}
+
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\n",
+			display,
+			formatDuration(entry.StoredAt, now.Sub(entry.StoredAt)),
+			formatDuration(entry.ExpiresAt, entry.ExpiresAt.Sub(now)),
+		)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	return tw.Flush()
+}
+
+type CachePurgeCliHandler struct {
+	Ecosystems []string `name:"ecosystem" help:"Only delete entries of the given ecosystems"`
+}
+
+func (h *CachePurgeCliHandler) Run(ctx context.Context, kv ports.KeyValueStore) (err error) {
+	defer func() {
+		err = errors.Join(err, kv.Close())
+	}()
+
+	var keys [][]byte
+	collect := func(entry ports.KeyValueEntry) error {
+		keys = append(keys, entry.Key)
+		return nil
+	}
+
+	// purging everything includes vulnerability reports, otherwise only the reports of the given ecosystems are purged
+	err = iterateEcosystems(ctx, kv, h.Ecosystems, collect)
+	if err == nil && len(h.Ecosystems) > 0 {
+		err = iterateVulnerabilityReports(ctx, kv, h.Ecosystems, collect)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to collect cache entries: %w", err)
+	}
+
+	if err := deleteKeys(ctx, kv, keys); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Purged cache entries", slog.Int("deleted", len(keys)))
+
+	return nil
+}
+
+type CacheInvalidateCliHandler struct {
+	Patterns []string `arg:"" help:"Package URL glob patterns e.g. 'pkg:npm/@types/*' - versions are ignored"`
+}
+
+func (h *CacheInvalidateCliHandler) Run(ctx context.Context, kv ports.KeyValueStore) (err error) {
+	defer func() {
+		err = errors.Join(err, kv.Close())
+	}()
+
+	patterns := make([]string, 0, len(h.Patterns))
+	for _, p := range h.Patterns {
+		if !strings.HasPrefix(p, "pkg:") {
+			p = "pkg:" + p
+		}
+
+		if idx := strings.LastIndex(p, "@"); idx > strings.LastIndex(p, "/") {
+			p = p[:idx]
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+
+		patterns = append(patterns, p)
+	}
+
+	var keys [][]byte
+	err = kv.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		// vulnerability reports of matching packages are invalidated as well
+		packageURLFromCacheKey := checker.PackageURLFromCacheKey
+		if vulnerability.IsCacheKey(entry.Key) {
+			packageURLFromCacheKey = vulnerability.PackageURLFromCacheKey
+		}
+
+		purl, err := packageURLFromCacheKey(entry.Key)
+		if err != nil {
+			return nil
+		}
+
+		candidate := displayPackageURL(purl)
+		for _, p := range patterns {
+			if matched, _ := path.Match(p, candidate); matched {
+				keys = append(keys, entry.Key)
+				return nil
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to collect cache entries: %w", err)
+	}
+
+	if err := deleteKeys(ctx, kv, keys); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Invalidated cache entries", slog.Int("deleted", len(keys)))
+
+	return nil
+}
+
+type CacheExportCliHandler struct {
+	Bundle string `arg:"" help:"Path to write the cache bundle to" type:"path"`
+}
+
+func (h *CacheExportCliHandler) Run(ctx context.Context, kv ports.KeyValueStore) (err error) {
+	defer func() {
+		err = errors.Join(err, kv.Close())
+	}()
+
+	bundleFile, err := os.Create(h.Bundle)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, bundleFile.Close())
+	}()
+
+	exported, err := db.ExportBundle(ctx, kv, bundleFile)
+	if err != nil {
+		return fmt.Errorf("failed to export cache: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Exported cache entries", slog.Int("entries", exported), slog.String("bundle", h.Bundle))
+
+	return nil
+}
+
+type CacheImportCliHandler struct {
+	Bundle *os.File      `arg:"" help:"Cache bundle to import"`
+	MaxAge time.Duration `name:"max-age" help:"Skip entries stored longer ago than this - 0 imports all unexpired entries" default:"0s"`
+}
+
+func (h *CacheImportCliHandler) Run(ctx context.Context, kv ports.KeyValueStore) (err error) {
+	defer func() {
+		err = errors.Join(err, h.Bundle.Close(), kv.Close())
+	}()
+
+	stats, err := db.ImportBundle(ctx, kv, h.Bundle, db.ImportOptions{MaxAge: h.MaxAge})
+	if err != nil {
+		return fmt.Errorf("failed to import cache: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Imported cache entries",
+		slog.Int("imported", stats.Imported),
+		slog.Int("skipped_expired", stats.Expired),
+		slog.Int("skipped_too_old", stats.TooOld),
+		slog.Int("skipped_outdated", stats.Outdated),
+	)
+
+	return nil
+}
+
+func iterateEcosystems(ctx context.Context, kv ports.KeyValueIterator, ecosystems []string, fn func(entry ports.KeyValueEntry) error) error {
+	if len(ecosystems) == 0 {
+		return kv.Iterate(ctx, nil, fn)
+	}
+
+	for _, ecosystem := range ecosystems {
+		if err := kv.Iterate(ctx, checker.CacheKeyPrefixFor(ecosystem), fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func iterateVulnerabilityReports(ctx context.Context, kv ports.KeyValueIterator, ecosystems []string, fn func(entry ports.KeyValueEntry) error) error {
+	for _, ecosystem := range ecosystems {
+		prefix := append([]byte(vulnerability.CacheKeyPrefix), checker.CacheKeyPrefixFor(ecosystem)...)
+		if err := kv.Iterate(ctx, prefix, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteKeys(ctx context.Context, kv ports.KeyValueDeleter, keys [][]byte) error {
+	for _, key := range keys {
+		if err := kv.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete cache entry %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// displayPackageURL renders the package URL without escaping and version to make it easier to match with globs.
+func displayPackageURL(purl packageurl.PackageURL) string {
+	if purl.Namespace == "" {
+		return fmt.Sprintf("pkg:%s/%s", purl.Type, purl.Name)
+	}
+
+	return fmt.Sprintf("pkg:%s/%s/%s", purl.Type, purl.Namespace, purl.Name)
+}
+
+func formatDuration(reference time.Time, d time.Duration) string {
+	if reference.IsZero() {
+		return "-"
+	}
+
+	return d.Round(time.Second).String()
+}
+
+func formatBytes(size int) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := unit, 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
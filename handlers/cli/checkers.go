@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gojek/heimdall/v7"
+	"github.com/gojek/heimdall/v7/hystrix"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
+	"github.com/prskr/aucs/infrastructure/checker/apk"
+	"github.com/prskr/aucs/infrastructure/checker/cocoapods"
+	"github.com/prskr/aucs/infrastructure/checker/conan"
+	"github.com/prskr/aucs/infrastructure/checker/conda"
+	"github.com/prskr/aucs/infrastructure/checker/cran"
+	"github.com/prskr/aucs/infrastructure/checker/deb"
+	"github.com/prskr/aucs/infrastructure/checker/github"
+	"github.com/prskr/aucs/infrastructure/checker/gittag"
+	"github.com/prskr/aucs/infrastructure/checker/helm"
+	"github.com/prskr/aucs/infrastructure/checker/hex"
+	"github.com/prskr/aucs/infrastructure/checker/npm"
+	"github.com/prskr/aucs/infrastructure/checker/nuget"
+	"github.com/prskr/aucs/infrastructure/checker/pub"
+	"github.com/prskr/aucs/infrastructure/checker/pypi"
+	"github.com/prskr/aucs/infrastructure/checker/rpm"
+	"github.com/prskr/aucs/infrastructure/checker/swift"
+	"github.com/prskr/aucs/infrastructure/checker/terraform"
+	"github.com/prskr/aucs/infrastructure/checker/vcpkg"
+	"github.com/prskr/aucs/infrastructure/metrics"
+	"github.com/prskr/aucs/infrastructure/telemetry"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+type HTTPClientFlag struct {
+	Timeout               time.Duration `name:"timeout" help:"HTTP client timeout" default:"30s"`
+	HystrixTimeout        time.Duration `name:"hystrix-timeout" help:"Hystrix timeout" default:"30s"`
+	MaxConcurrentRequests int           `name:"max-concurrent-requests" help:"Maximum concurrent requests" default:"100"`
+	Retry                 struct {
+		InitialTimeout time.Duration `name:"initial-timeout" help:"Initial retry timeout" default:"1s"`
+		MaxTimeout     time.Duration `name:"max-timeout" help:"Maximum retry timeout" default:"10s"`
+		ExponentFactor float64       `name:"exponent-factor" help:"Exponential backoff factor" default:"2"`
+		MaximumJitter  time.Duration `name:"maximum-jitter" help:"Maximum retry jitter" default:"200ms"`
+	} `embed:"" prefix:"retry."`
+}
+
+func (f HTTPClientFlag) Retrier() heimdall.Retriable {
+	backoff := heimdall.NewExponentialBackoff(
+		f.Retry.InitialTimeout,
+		f.Retry.MaxTimeout,
+		f.Retry.ExponentFactor,
+		f.Retry.MaximumJitter,
+	)
+
+	return heimdall.NewRetrier(backoff)
+}
+
+func (f HTTPClientFlag) Client(commandName string, retier heimdall.Retriable, m *metrics.Metrics) *http.Client {
+	hystrixClient := hystrix.NewClient(
+		hystrix.WithCommandName(commandName),
+		hystrix.WithHTTPTimeout(f.Timeout),
+		hystrix.WithHystrixTimeout(f.HystrixTimeout),
+		hystrix.WithMaxConcurrentRequests(f.MaxConcurrentRequests),
+		hystrix.WithRetrier(retier),
+	)
+
+	transport := m.InstrumentTransport(commandName, hystrixRoundtrip{client: hystrixClient})
+
+	return &http.Client{Transport: otelhttp.NewTransport(transport)}
+}
+
+func newCheckerRegistry(
+	kv ports.KeyValueStore,
+	dbFlag DBFlag,
+	httpClientFlag HTTPClientFlag,
+	registryFlag RegistryFlag,
+	qualifierHosts httpx.AllowedHosts,
+	m *metrics.Metrics,
+) *checker.Registry {
+	retrier := httpClientFlag.Retrier()
+
+	pubChecker := pub.NewChecker(httpClientFlag.Client("CheckLatestPubVersion", retrier, m), registryFlag.PubURL)
+	pubChecker.QualifierHosts = qualifierHosts
+
+	terraformChecker := terraform.NewChecker(
+		httpClientFlag.Client("CheckLatestTerraformVersion", retrier, m),
+		registryFlag.TerraformURL,
+		registryFlag.terraformTokens(),
+	)
+	terraformChecker.QualifierHosts = qualifierHosts
+
+	condaChecker := conda.NewChecker(
+		httpClientFlag.Client("CheckLatestCondaVersion", retrier, m),
+		registryFlag.CondaChannelAlias,
+		registryFlag.CondaChannels,
+	)
+	condaChecker.QualifierHosts = qualifierHosts
+
+	conanChecker := conan.NewChecker(httpClientFlag.Client("CheckLatestConanVersion", retrier, m), registryFlag.ConanURL)
+	conanChecker.QualifierHosts = qualifierHosts
+
+	helmChecker := helm.NewChecker(httpClientFlag.Client("CheckLatestHelmVersion", retrier, m))
+	helmChecker.QualifierHosts = qualifierHosts
+
+	gitTagChecker := gittag.NewChecker(httpClientFlag.Client("CheckLatestGitTag", retrier, m))
+	gitTagChecker.QualifierHosts = qualifierHosts
+
+	swiftChecker := swift.NewChecker(httpClientFlag.Client("CheckLatestSwiftVersion", retrier, m), registryFlag.SwiftHosts)
+	swiftChecker.QualifierHosts = qualifierHosts
+
+	registry := checker.NewRegistry(telemetry.TraceKVStore(kv))
+	registry.Policy = dbFlag.CachePolicy()
+	registry.Observer = m
+	registry.Register(
+		m.InstrumentChecker(nuget.NewChecker(httpClientFlag.Client("CheckLatestNugetVersion", retrier, m))),
+		m.InstrumentChecker(npm.NewChecker(httpClientFlag.Client("CheckLatestNPMVersion", retrier, m))),
+		m.InstrumentChecker(pypi.NewChecker(httpClientFlag.Client("CheckLatestPyPiVersion", retrier, m))),
+		m.InstrumentChecker(hex.NewChecker(httpClientFlag.Client("CheckLatestHexVersion", retrier, m), registryFlag.HexURL)),
+		m.InstrumentChecker(pubChecker),
+		m.InstrumentChecker(github.NewChecker(
+			httpClientFlag.Client("CheckLatestGitHubTag", retrier, m),
+			registryFlag.GitHubURL,
+			registryFlag.GitHubToken,
+		)),
+		m.InstrumentChecker(gitTagChecker),
+		m.InstrumentChecker(terraformChecker),
+		m.InstrumentChecker(condaChecker),
+		m.InstrumentChecker(cocoapods.NewChecker(httpClientFlag.Client("CheckLatestCocoaPodsVersion", retrier, m), registryFlag.CocoaPodsURL)),
+		m.InstrumentChecker(swiftChecker),
+		m.InstrumentChecker(conanChecker),
+		m.InstrumentChecker(vcpkg.NewChecker(registryFlag.VcpkgRoot)),
+		m.InstrumentChecker(cran.NewChecker(httpClientFlag.Client("CheckLatestCRANVersion", retrier, m), registryFlag.CRANURL)),
+		m.InstrumentChecker(cran.NewBioconductorChecker(
+			httpClientFlag.Client("CheckLatestBioconductorVersion", retrier, m),
+			registryFlag.BioconductorURL,
+		)),
+		m.InstrumentChecker(helmChecker),
+		m.InstrumentChecker(deb.NewChecker(httpClientFlag.Client("CheckLatestDebVersion", retrier, m), registryFlag.DebMirrors)),
+		m.InstrumentChecker(rpm.NewChecker(httpClientFlag.Client("CheckLatestRPMVersion", retrier, m), registryFlag.RPMRepositories)),
+		m.InstrumentChecker(apk.NewChecker(httpClientFlag.Client("CheckLatestAPKVersion", retrier, m), registryFlag.APKMirrors)),
+	)
+
+	return registry
+}
+
+var _ http.RoundTripper = (*hystrixRoundtrip)(nil)
+
+type hystrixRoundtrip struct {
+	client *hystrix.Client
+}
+
+// RoundTrip implements http.RoundTripper.
+func (h hystrixRoundtrip) RoundTrip(req *http.Request) (*http.Response, error) {
+	return h.client.Do(req)
+}
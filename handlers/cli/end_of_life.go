@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/eol"
+)
+
+type EndOfLifeFlag struct {
+	Files []string `name:"file" help:"JSON files with product release cycles modeled after endoflife.date to check components for their end-of-life" type:"existingfile"`
+}
+
+// Open returns nil if no end-of-life data is configured.
+func (f EndOfLifeFlag) Open() (ports.EndOfLifeSource, error) {
+	if len(f.Files) == 0 {
+		return nil, nil
+	}
+
+	return eol.NewFileSource(f.Files...)
+}
@@ -4,114 +4,181 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
-	"net/http"
 	"os"
-	"sync"
+	"path/filepath"
 	"time"
 
 	"github.com/CycloneDX/cyclonedx-go"
-	"github.com/gojek/heimdall/v7"
-	"github.com/gojek/heimdall/v7/hystrix"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/core/services"
 	"github.com/prskr/aucs/infrastructure/checker"
-	"github.com/prskr/aucs/infrastructure/checker/npm"
-	"github.com/prskr/aucs/infrastructure/checker/nuget"
-	"github.com/prskr/aucs/infrastructure/checker/pypi"
+	"github.com/prskr/aucs/infrastructure/metrics"
+	"github.com/prskr/aucs/internal/httpx"
 )
 
+var tracer = otel.Tracer("github.com/prskr/aucs/handlers/cli")
+
+var ErrErrorRatioExceeded = errors.New("ratio of failed lookups exceeds threshold")
+
 type EnrichCLiHandler struct {
 	SBOMFile *os.File `arg:"" help:"SBOM file to enrich"`
 
-	BOMFormat       BOMFileFormatFlag `name:"bom-format" help:"BOM file format" default:"json"`
-	DB              DBFlag            `embed:"" prefix:"db."`
-	Parallelism     uint8             `name:"parallelism" help:"Number of parallel requests" default:"20"`
-	WriteBackToFile bool              `name:"write" help:"If aucs should write the SBOM to the source file - if not will be written to STDOUT" default:"false"`
-
-	HttpClient struct {
-		Timeout               time.Duration `name:"timeout" help:"HTTP client timeout" default:"30s"`
-		HystrixTimeout        time.Duration `name:"hystrix-timeout" help:"Hystrix timeout" default:"30s"`
-		MaxConcurrentRequests int           `name:"max-concurrent-requests" help:"Maximum concurrent requests" default:"100"`
-		Retry                 struct {
-			InitialTimeout time.Duration `name:"initial-timeout" help:"Initial retry timeout" default:"1s"`
-			MaxTimeout     time.Duration `name:"max-timeout" help:"Maximum retry timeout" default:"10s"`
-			ExponentFactor float64       `name:"exponent-factor" help:"Exponential backoff factor" default:"2"`
-			MaximumJitter  time.Duration `name:"maximum-jitter" help:"Maximum retry jitter" default:"200ms"`
-		} `embed:"" prefix:"retry."`
-	} `embed:"" prefix:"http-client."`
-
-	KV       ports.KeyValueStore `kong:"-"`
-	Checkers *checker.Registry   `kong:"-"`
+	BOMFormat        BOMFileFormatFlag `name:"bom-format" help:"BOM file format" default:"json"`
+	DB               DBFlag            `embed:"" prefix:"db."`
+	Parallelism      uint8             `name:"parallelism" help:"Number of parallel requests" default:"20"`
+	WriteBackToFile  bool              `name:"write" help:"If aucs should write the SBOM to the source file - if not will be written to STDOUT" default:"false"`
+	HttpClient       HTTPClientFlag    `embed:"" prefix:"http-client."`
+	Registries       RegistryFlag      `embed:"" prefix:"registry."`
+	Vulnerabilities  VulnerabilityFlag `embed:"" prefix:"vulnerabilities."`
+	EndOfLife        EndOfLifeFlag     `embed:"" prefix:"eol."`
+	MetricsTextfile  string            `name:"metrics.textfile" help:"Write Prometheus metrics to this file after the run e.g. for the node exporter textfile collector" type:"path"`
+	SummaryFile      string            `name:"summary-file" help:"Write a JSON summary of all lookup outcomes to this file" type:"path"`
+	FailOnErrorRatio float64           `name:"fail-on-error-ratio" help:"Exit with an error if the ratio of failed lookups exceeds this threshold - unsupported package types and components without package URL are not counted" default:"1"`
+	Timeout          time.Duration     `name:"timeout" help:"Abort the enrichment if it takes longer - 0 disables the deadline" default:"0s"`
+	Strip            bool              `name:"strip" help:"Remove all data previously added by aucs instead of enriching the SBOM" default:"false"`
+
+	KV         ports.KeyValueStore       `kong:"-"`
+	Checkers   *checker.Registry         `kong:"-"`
+	Metrics    *metrics.Metrics          `kong:"-"`
+	VulnSource ports.VulnerabilitySource `kong:"-"`
+	EOLSource  ports.EndOfLifeSource     `kong:"-"`
 }
 
-func (h *EnrichCLiHandler) Run(ctx context.Context, stdout ports.STDOUT) (err error) {
+func (h *EnrichCLiHandler) Run(ctx context.Context, stdout ports.STDOUT, stderr ports.STDERR) (err error) {
+	ctx, span := tracer.Start(ctx, "EnrichCLiHandler.Run")
+	defer span.End()
+
+	if h.Strip {
+		return h.strip(ctx, stdout)
+	}
+
 	defer func() {
+		h.Checkers.Wait()
 		err = errors.Join(err, h.SBOMFile.Close(), h.KV.Close())
 	}()
 
-	decoder := cyclonedx.NewBOMDecoder(h.SBOMFile, h.BOMFormat.Format)
-	bom := cyclonedx.NewBOM()
+	bom, err := h.decodeBOM(ctx)
+	if err != nil {
+		return err
+	}
 
-	if err := decoder.Decode(bom); err != nil {
-		return fmt.Errorf("failed to decode BOM: %w", err)
+	enricher := services.BOMEnricher{
+		Lookup:          h.Checkers,
+		Parallelism:     h.Parallelism,
+		Timeout:         h.Timeout,
+		Version:         aucsVersion(),
+		Vulnerabilities: h.VulnSource,
+		EndOfLife:       h.EOLSource,
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(*bom.Components))
+	summary, err := enricher.Enrich(ctx, bom)
+	if err != nil {
+		return errors.Join(err, h.reportSummary(stderr, summary))
+	}
 
-	scanInput := make(chan *cyclonedx.Component, h.Parallelism)
-	processCtx, cancel := context.WithCancel(ctx)
+	if err := h.writeBOM(bom, stdout); err != nil {
+		return err
+	}
 
-	for range h.Parallelism {
-		go func() {
-			for {
-				select {
-				case in := <-scanInput:
-					h.processComponent(processCtx, in)
-					wg.Done()
-				case <-processCtx.Done():
-					return
-				}
-			}
-		}()
+	if h.MetricsTextfile != "" {
+		if err := h.Metrics.WriteTextfile(h.MetricsTextfile); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
 	}
 
-	for _, c := range *bom.Components {
-		scanInput <- &c
+	if err := h.reportSummary(stderr, summary); err != nil {
+		return err
 	}
 
-	wg.Wait()
-	cancel()
+	if ratio := summary.ErrorRatio(); ratio > h.FailOnErrorRatio {
+		return fmt.Errorf("%w: %.2f > %.2f", ErrErrorRatioExceeded, ratio, h.FailOnErrorRatio)
+	}
 
-	out := stdout
-	if h.WriteBackToFile {
-		if _, err := h.SBOMFile.Seek(0, 0); err != nil {
-			return fmt.Errorf("failed to seek to the beginning of the SBOM file: %w", err)
-		}
+	return nil
+}
 
-		out = h.SBOMFile
+// strip removes all data previously added by aucs - neither the database nor any registry is required.
+func (h *EnrichCLiHandler) strip(ctx context.Context, stdout ports.STDOUT) (err error) {
+	defer func() {
+		err = errors.Join(err, h.SBOMFile.Close())
+	}()
+
+	bom, err := h.decodeBOM(ctx)
+	if err != nil {
+		return err
 	}
 
-	encoder := cyclonedx.NewBOMEncoder(out, h.BOMFormat.Format)
+	services.StripBOM(bom)
 
-	return encoder.Encode(bom)
+	return h.writeBOM(bom, stdout)
 }
 
-func (h *EnrichCLiHandler) processComponent(ctx context.Context, in *cyclonedx.Component) {
-	if info, err := h.Checkers.LatestVersionFor(ctx, in.PackageURL); err != nil {
-		slog.WarnContext(ctx, "Failed to determine latest version for package", slog.String("package_url", in.PackageURL), slog.String("err", err.Error()))
-	} else {
-		slog.DebugContext(ctx, "Found latest package version",
-			slog.String("package_url", in.PackageURL),
-			slog.String("latest_version", info.LatestVersion),
-			slog.String("current_version", in.Version),
-		)
-
-		if in.Properties == nil {
-			*in.Properties = make([]cyclonedx.Property, 0)
+func (h *EnrichCLiHandler) reportSummary(stderr ports.STDERR, summary services.EnrichSummary) error {
+	if err := writeSummary(stderr, summary); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	if h.SummaryFile != "" {
+		if err := writeSummaryFile(h.SummaryFile, summary); err != nil {
+			return fmt.Errorf("failed to write summary file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeBOM writes the BOM to STDOUT or replaces the source file atomically.
+// The source file is opened read-only hence it can't be written directly.
+func (h *EnrichCLiHandler) writeBOM(bom *cyclonedx.BOM, stdout ports.STDOUT) (err error) {
+	if !h.WriteBackToFile {
+		return cyclonedx.NewBOMEncoder(stdout, h.BOMFormat.Format).Encode(bom)
+	}
+
+	path := h.SBOMFile.Name()
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary SBOM file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
 		}
-		*in.Properties = append(*in.Properties, cyclonedx.Property{Name: "aucs:package:latest_version", Value: info.LatestVersion})
+	}()
+
+	if err := cyclonedx.NewBOMEncoder(tmpFile, h.BOMFormat.Format).Encode(bom); err != nil {
+		_ = tmpFile.Close()
+		return err
 	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if info, err := h.SBOMFile.Stat(); err == nil {
+		_ = os.Chmod(tmpFile.Name(), info.Mode().Perm())
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+func (h *EnrichCLiHandler) decodeBOM(ctx context.Context) (*cyclonedx.BOM, error) {
+	_, span := tracer.Start(ctx, "DecodeBOM")
+	defer span.End()
+
+	decoder := cyclonedx.NewBOMDecoder(h.SBOMFile, h.BOMFormat.Format)
+	bom := cyclonedx.NewBOM()
+
+	if err := decoder.Decode(bom); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("failed to decode BOM: %w", err)
+	}
+
+	return bom, nil
 }
 
 func (h *EnrichCLiHandler) AfterApply() error {
@@ -119,51 +186,36 @@ func (h *EnrichCLiHandler) AfterApply() error {
 		return errors.New("missing SBOM file")
 	}
 
+	if h.FailOnErrorRatio < 0 || h.FailOnErrorRatio > 1 {
+		return fmt.Errorf("fail-on-error-ratio has to be between 0 and 1 but was %.2f", h.FailOnErrorRatio)
+	}
+
+	// stripping doesn't look up any package
+	if h.Strip {
+		return nil
+	}
+
 	if kv, err := h.DB.Open(); err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	} else {
 		h.KV = kv
 	}
 
-	backoff := heimdall.NewExponentialBackoff(
-		h.HttpClient.Retry.InitialTimeout,
-		h.HttpClient.Retry.MaxTimeout,
-		h.HttpClient.Retry.ExponentFactor,
-		h.HttpClient.Retry.MaximumJitter,
-	)
+	h.Metrics = metrics.New()
+	// package URLs are taken from the local BOM - qualifiers may select any host
+	h.Checkers = newCheckerRegistry(h.KV, h.DB, h.HttpClient, h.Registries, httpx.AllowedHosts{}, h.Metrics)
 
-	retrier := heimdall.NewRetrier(backoff)
+	if source, err := h.Vulnerabilities.Open(h.KV, h.HttpClient, h.Metrics); err != nil {
+		return errors.Join(fmt.Errorf("failed to setup vulnerability source: %w", err), h.KV.Close())
+	} else {
+		h.VulnSource = source
+	}
 
-	h.Checkers = checker.NewRegistry(h.KV)
-	h.Checkers.Register(
-		nuget.NewChecker(h.heimdallClient("CheckLatestNugetVersion", retrier)),
-		npm.NewChecker(h.heimdallClient("CheckLatestNPMVersion", retrier)),
-		pypi.NewChecker(h.heimdallClient("CheckLatestPyPiVersion", retrier)),
-	)
+	if source, err := h.EndOfLife.Open(); err != nil {
+		return errors.Join(fmt.Errorf("failed to setup end-of-life source: %w", err), h.KV.Close())
+	} else {
+		h.EOLSource = source
+	}
 
 	return nil
 }
-
-func (h *EnrichCLiHandler) heimdallClient(commandName string, retier heimdall.Retriable) *http.Client {
-	hystrixClient := hystrix.NewClient(
-		hystrix.WithCommandName(commandName),
-		hystrix.WithHTTPTimeout(h.HttpClient.Timeout),
-		hystrix.WithCommandName("MyCommand"),
-		hystrix.WithHystrixTimeout(h.HttpClient.HystrixTimeout),
-		hystrix.WithMaxConcurrentRequests(h.HttpClient.MaxConcurrentRequests),
-		hystrix.WithRetrier(retier),
-	)
-
-	return &http.Client{Transport: hystrixRoundtrip{client: hystrixClient}}
-}
-
-var _ http.RoundTripper = (*hystrixRoundtrip)(nil)
-
-type hystrixRoundtrip struct {
-	client *hystrix.Client
-}
-
-// RoundTrip implements http.RoundTripper.
-func (h hystrixRoundtrip) RoundTrip(req *http.Request) (*http.Response, error) {
-	return h.client.Do(req)
-}
@@ -1,17 +1,72 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
 	"time"
 
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
 	"github.com/prskr/aucs/infrastructure/db"
 )
 
 type DBFlag struct {
-	Path string        `name:"path" help:"Path to the database data directory" default:"${XDG_CACHE_HOME}/aucs/db"`
-	TTL  time.Duration `name:"ttl" help:"Time to live for dependency look entries" default:"6h"`
+	Driver               string        `name:"driver" help:"Storage backend of the cache (${enum})" enum:"badger,sqlite,json,memory,redis" default:"badger"`
+	Path                 string        `name:"path" help:"Path to the database data directory" default:"${XDG_CACHE_HOME}/aucs/db"`
+	URL                  string        `name:"url" help:"URL of the server for the redis driver e.g. redis://:password@localhost:6379/0?prefix=aucs:"`
+	TTL                  time.Duration `name:"ttl" help:"Time to live for dependency look entries" default:"6h"`
+	NegativeTTL          time.Duration `name:"negative-ttl" help:"Time to live for not found or ambiguous lookup results" default:"1h"`
+	StaleWhileRevalidate time.Duration `name:"stale-while-revalidate" help:"Grace period after expiry in which stale entries are served while refreshed in the background" default:"1h"`
+	RefreshTimeout       time.Duration `name:"refresh-timeout" help:"Timeout for background refreshes of stale entries" default:"30s"`
+	LockTimeout          time.Duration `name:"lock-timeout" help:"How long to wait for another process to release the badger database" default:"10s"`
+	LockFallback         string        `name:"lock-fallback" help:"What to do if the badger database is still locked after the lock timeout (${enum}) - memory starts with an empty cache and writes it back on exit" enum:"memory,fail" default:"memory"`
 }
 
 func (f DBFlag) Open() (ports.KeyValueStore, error) {
-	return db.NewBadgerKVStore(f.Path, f.TTL)
+	switch f.Driver {
+	case "badger":
+		return f.openBadger()
+	case "sqlite":
+		return db.NewSQLiteKVStore(filepath.Join(f.Path, "cache.sqlite"), f.TTL)
+	case "json":
+		return db.NewJSONFileKVStore(filepath.Join(f.Path, "cache.json"), f.TTL)
+	case "memory":
+		return db.NewMemoryKVStore(f.TTL), nil
+	case "redis":
+		return db.NewRESPKVStore(f.URL, f.TTL)
+	default:
+		return nil, fmt.Errorf("unknown database driver: %s", f.Driver)
+	}
+}
+
+// openBadger waits for other aucs processes to release the database.
+// If the lock isn't released in time, all entries are buffered in memory and merged into the database on close.
+// The locked database can't be read in the meantime, hence the fallback starts with an empty cache.
+func (f DBFlag) openBadger() (ports.KeyValueStore, error) {
+	store, err := db.WaitForBadgerKVStore(f.Path, f.TTL, f.LockTimeout)
+	if err == nil {
+		return store, nil
+	}
+
+	if !errors.Is(err, db.ErrDatabaseLocked) || f.LockFallback != "memory" {
+		return nil, err
+	}
+
+	slog.Warn("Database is locked by another process - starting with an empty cache buffered in memory",
+		slog.String("path", f.Path),
+		slog.Duration("lock_timeout", f.LockTimeout),
+	)
+
+	return db.NewBadgerWriteBackKVStore(f.Path, f.TTL, f.LockTimeout), nil
+}
+
+func (f DBFlag) CachePolicy() checker.CachePolicy {
+	return checker.CachePolicy{
+		TTL:                  f.TTL,
+		NegativeTTL:          f.NegativeTTL,
+		StaleWhileRevalidate: f.StaleWhileRevalidate,
+		RefreshTimeout:       f.RefreshTimeout,
+	}
 }
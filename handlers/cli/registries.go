@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/prskr/aucs/infrastructure/checker/terraform"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+// RegistryFlag configures the base URLs of registries e.g. to use mirrors or self-hosted instances.
+type RegistryFlag struct {
+	HexURL      string `name:"hex-url" help:"Base URL of the hex.pm API or a self-hosted hex repository" default:"${HEX_BASE_URL}"`
+	PubURL      string `name:"pub-url" help:"Base URL of the pub repository - packages can override it with the repository_url qualifier" default:"${PUB_BASE_URL}" env:"PUB_HOSTED_URL"`
+	GitHubURL   string `name:"github-url" help:"Base URL of the GitHub REST API e.g. https://github.example.com/api/v3 for GitHub Enterprise Server" default:"${GITHUB_BASE_URL}" env:"GITHUB_API_URL"`
+	GitHubToken string `name:"github-token" help:"Token to authenticate against the GitHub REST API to raise the rate limit" env:"GITHUB_TOKEN"`
+
+	TerraformURL    string            `name:"terraform-url" help:"Hostname or URL of the default Terraform registry - packages can override it with the repository_url qualifier" default:"${TERRAFORM_BASE_URL}"`
+	TerraformTokens map[string]string `name:"terraform-token" help:"API tokens of private Terraform registries by hostname e.g. app.terraform.io=<token> - TF_TOKEN_<host> environment variables are honoured as well"`
+
+	CondaChannelAlias string            `name:"conda-channel-alias" help:"URL prepended to conda channel names e.g. conda-forge" default:"${CONDA_CHANNEL_ALIAS}"`
+	CondaChannels     map[string]string `name:"conda-channel" help:"URLs of conda channels by name e.g. conda-forge=https://mirror.example.com/conda-forge"`
+
+	CocoaPodsURL string            `name:"cocoapods-url" help:"Base URL of the CocoaPods trunk CDN serving the all_pods_versions shards" default:"${COCOAPODS_BASE_URL}"`
+	SwiftHosts   map[string]string `name:"swift-host" help:"Base URLs of git servers by the host in the namespace of Swift packages e.g. github.com=https://git-mirror.example.com/github"`
+
+	ConanURL  string `name:"conan-url" help:"URL of the Conan remote e.g. an Artifactory repository - packages can override it with the repository_url qualifier" default:"${CONAN_BASE_URL}"`
+	VcpkgRoot string `name:"vcpkg-root" help:"Path to a checkout of the vcpkg registry whose versions/baseline.json is used - vcpkg ports are skipped if not set" env:"VCPKG_ROOT"`
+
+	CRANURL         string `name:"cran-url" help:"Base URL of the CRAN mirror" default:"${CRAN_BASE_URL}"`
+	BioconductorURL string `name:"bioconductor-url" help:"Base URL of the Bioconductor repositories - the release is taken from the release qualifier" default:"${BIOCONDUCTOR_BASE_URL}"`
+
+	DebMirrors      map[string]string `name:"deb-mirror" help:"Mirrors of Debian based distributions e.g. debian=https://mirror.example.com/debian - security suites are configured with the -security suffix e.g. debian-security=https://mirror.example.com/debian-security"`
+	RPMRepositories map[string]string `name:"rpm-repository" help:"Repository URL templates of RPM based distributions - {release}, {major} and {arch} are substituted e.g. fedora=https://mirror.example.com/fedora/{release}/{arch}/"`
+	APKMirrors      map[string]string `name:"apk-mirror" help:"Mirrors of apk based distributions e.g. alpine=https://mirror.example.com/alpine"`
+
+	QualifierHosts []string `name:"qualifier-host" help:"Hosts the repository_url, channel and vcs_url qualifiers and the namespace of Swift packages submitted to the API may select in addition to the configured registries - * allows all hosts"`
+}
+
+// qualifierHosts restricts the hosts package URL qualifiers may select to the configured registries and the
+// explicitly allowed hosts - otherwise API clients could make the server request arbitrary URLs.
+func (f RegistryFlag) qualifierHosts() httpx.AllowedHosts {
+	if slices.Contains(f.QualifierHosts, "*") {
+		return httpx.AllowedHosts{}
+	}
+
+	hosts := append(slices.Clone(f.QualifierHosts), f.PubURL, terraform.RegistryURL(f.TerraformURL), f.ConanURL, f.CondaChannelAlias)
+	hosts = slices.AppendSeq(hosts, maps.Values(f.CondaChannels))
+
+	return httpx.AllowHosts(hosts...)
+}
+
+// terraformTokens merges the TF_TOKEN_<host> environment variables with the explicitly configured tokens.
+func (f RegistryFlag) terraformTokens() map[string]string {
+	tokens := terraform.TokensFromEnv(os.Environ())
+	maps.Copy(tokens, f.TerraformTokens)
+
+	return tokens
+}
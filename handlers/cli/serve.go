@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/handlers/api"
+	"github.com/prskr/aucs/infrastructure/checker"
+	"github.com/prskr/aucs/infrastructure/metrics"
+)
+
+type ServeCliHandler struct {
+	Address         string            `name:"address" help:"Address the HTTP server listens on" default:":8080"`
+	ShutdownTimeout time.Duration     `name:"shutdown-timeout" help:"Time to wait for in-flight requests when shutting down" default:"15s"`
+	DB              DBFlag            `embed:"" prefix:"db."`
+	Parallelism     uint8             `name:"parallelism" help:"Number of parallel requests per batch or SBOM" default:"20"`
+	HttpClient      HTTPClientFlag    `embed:"" prefix:"http-client."`
+	Registries      RegistryFlag      `embed:"" prefix:"registry."`
+	Vulnerabilities VulnerabilityFlag `embed:"" prefix:"vulnerabilities."`
+	EndOfLife       EndOfLifeFlag     `embed:"" prefix:"eol."`
+
+	KV         ports.KeyValueStore       `kong:"-"`
+	Checkers   *checker.Registry         `kong:"-"`
+	Metrics    *metrics.Metrics          `kong:"-"`
+	VulnSource ports.VulnerabilitySource `kong:"-"`
+	EOLSource  ports.EndOfLifeSource     `kong:"-"`
+}
+
+func (h *ServeCliHandler) Run(ctx context.Context) (err error) {
+	defer func() {
+		h.Checkers.Wait()
+		err = errors.Join(err, h.KV.Close())
+	}()
+
+	apiServer := api.NewServer(h.Checkers, h.Parallelism)
+	apiServer.MetricsHandler = h.Metrics.Handler()
+	apiServer.Version = aucsVersion()
+	apiServer.Vulnerabilities = h.VulnSource
+	apiServer.EndOfLife = h.EOLSource
+
+	httpServer := &http.Server{
+		Addr:              h.Address,
+		Handler:           otelhttp.NewHandler(apiServer.Handler(), "aucs", otelhttp.WithSpanNameFormatter(spanNameForRequest)),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithoutCancel(ctx)
+		},
+	}
+
+	// the server is only ready once it accepts connections
+	listener, err := net.Listen("tcp", h.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", h.Address, err)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.InfoContext(ctx, "Starting HTTP server", slog.String("address", listener.Addr().String()))
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	apiServer.SetReady(true)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.InfoContext(ctx, "Shutting down HTTP server")
+	apiServer.SetReady(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (h *ServeCliHandler) AfterApply() error {
+	if kv, err := h.DB.Open(); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	} else {
+		h.KV = kv
+	}
+
+	h.Metrics = metrics.New()
+	h.Checkers = newCheckerRegistry(h.KV, h.DB, h.HttpClient, h.Registries, h.Registries.qualifierHosts(), h.Metrics)
+
+	if source, err := h.Vulnerabilities.Open(h.KV, h.HttpClient, h.Metrics); err != nil {
+		return errors.Join(fmt.Errorf("failed to setup vulnerability source: %w", err), h.KV.Close())
+	} else {
+		h.VulnSource = source
+	}
+
+	if source, err := h.EndOfLife.Open(); err != nil {
+		return errors.Join(fmt.Errorf("failed to setup end-of-life source: %w", err), h.KV.Close())
+	} else {
+		h.EOLSource = source
+	}
+
+	return nil
+}
+
+func spanNameForRequest(_ string, r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prskr/aucs/core/services"
+)
+
+func writeSummary(w io.Writer, summary services.EnrichSummary) error {
+	_, _ = fmt.Fprintf(w, "Processed %d components in %s\n", summary.Components, summary.Duration.Round(time.Millisecond))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "OUTCOME\tCOMPONENTS")
+
+	for _, outcome := range services.ComponentOutcomes {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\n", strings.ReplaceAll(string(outcome), "_", " "), summary.Outcomes[outcome])
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "Error ratio: %.2f\n", summary.ErrorRatio())
+
+	return err
+}
+
+// writeSummaryFile writes the summary as JSON - the file is replaced atomically.
+func writeSummaryFile(path string, summary services.EnrichSummary) (err error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	encoder := json.NewEncoder(tmpFile)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(summaryReport{EnrichSummary: summary, ErrorRatio: summary.ErrorRatio()}); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+type summaryReport struct {
+	services.EnrichSummary
+	ErrorRatio float64 `json:"error_ratio"`
+}
@@ -0,0 +1,12 @@
+package cli
+
+import "runtime/debug"
+
+// aucsVersion returns the module version aucs was built from - "(devel)" for local builds.
+func aucsVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "(devel)"
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/metrics"
+	"github.com/prskr/aucs/infrastructure/telemetry"
+	"github.com/prskr/aucs/infrastructure/vulnerability"
+	"github.com/prskr/aucs/infrastructure/vulnerability/osv"
+)
+
+type VulnerabilityFlag struct {
+	Source  string   `name:"source" help:"Where to look up known vulnerabilities of the current versions (${enum})" enum:"none,osv,osv-offline" default:"none"`
+	OSVURL  string   `name:"osv-url" help:"Base URL of the OSV API" default:"${OSV_BASE_URL}"`
+	OSVDump []string `name:"osv-dump" help:"OSV zip dumps e.g. https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip used by the osv-offline source" type:"existingfile"`
+}
+
+// Open returns nil if vulnerability lookups are disabled.
+func (f VulnerabilityFlag) Open(kv ports.KeyValueStore, httpClientFlag HTTPClientFlag, m *metrics.Metrics) (ports.VulnerabilitySource, error) {
+	switch f.Source {
+	case "none":
+		return nil, nil
+	case "osv":
+		client := httpClientFlag.Client("QueryOSVVulnerabilities", httpClientFlag.Retrier(), m)
+		return vulnerability.NewCachedSource(telemetry.TraceKVStore(kv), osv.NewSource(client, f.OSVURL)), nil
+	case "osv-offline":
+		if len(f.OSVDump) == 0 {
+			return nil, fmt.Errorf("the osv-offline source requires at least one OSV dump")
+		}
+
+		return osv.NewOfflineSource(f.OSVDump...)
+	default:
+		return nil, fmt.Errorf("unknown vulnerability source: %s", f.Source)
+	}
+}
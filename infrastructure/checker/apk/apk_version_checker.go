@@ -0,0 +1,221 @@
+package apk
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+const (
+	distroQualifier = "distro"
+	archQualifier   = "arch"
+	defaultArch     = "x86_64"
+)
+
+// DefaultMirrors are the mirrors of the supported distributions indexed by the package URL namespace.
+var DefaultMirrors = map[string]string{
+	"alpine": "https://dl-cdn.alpinelinux.org/alpine",
+}
+
+// repositories that are searched for packages.
+var repositories = []string{"main", "community"}
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker uses the DefaultMirrors unless they're overridden by mirrors.
+func NewChecker(client *http.Client, mirrors map[string]string) Checker {
+	merged := make(map[string]string, len(DefaultMirrors)+len(mirrors))
+	for distribution, mirror := range DefaultMirrors {
+		merged[distribution] = mirror
+	}
+
+	for distribution, mirror := range mirrors {
+		merged[distribution] = mirror
+	}
+
+	return Checker{
+		Client:  client,
+		Mirrors: merged,
+		indices: memo.New[packageIndex](),
+	}
+}
+
+// Checker looks up the latest version of Alpine packages in the APKINDEX of the release branch taken from the distro
+// qualifier. Indices are downloaded once and shared by all lookups hence the checker has to be created with NewChecker.
+type Checker struct {
+	Client  *http.Client
+	Mirrors map[string]string
+
+	indices *memo.Cache[packageIndex]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "apk"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	mirror, ok := c.Mirrors[packageUrl.Namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w: no mirror for distribution %q", ports.ErrNoCheckerForPackageType, packageUrl.Namespace)
+	}
+
+	qualifiers := packageUrl.Qualifiers.Map()
+
+	branch, ok := branchFor(packageUrl.Namespace, qualifiers[distroQualifier])
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown release %q of %s", ports.ErrNoCheckerForPackageType, qualifiers[distroQualifier], packageUrl.Namespace)
+	}
+
+	arch := qualifiers[archQualifier]
+	if arch == "" || arch == "noarch" {
+		arch = defaultArch
+	}
+
+	var latest string
+
+	for _, repository := range repositories {
+		indexURL := httpx.BaseURL(mirror) + path.Join(branch, repository, arch, "APKINDEX.tar.gz")
+
+		index, err := c.indices.Get(ctx, indexURL, func(ctx context.Context) (packageIndex, error) {
+			return c.fetchIndex(ctx, indexURL)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if version, ok := index[packageUrl.Name]; ok && (latest == "" || CompareVersions(version, latest) > 0) {
+			latest = version
+		}
+	}
+
+	if latest == "" {
+		return nil, fmt.Errorf("%w: %s in %s", ports.ErrNoMatchingPackageFound, packageUrl.Name, branch)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latest,
+		PackageManager: "apk",
+	}, nil
+}
+
+// fetchIndex treats missing indices e.g. of a repository that doesn't exist for an architecture as empty.
+func (c Checker) fetchIndex(ctx context.Context, indexURL string) (index packageIndex, err error) {
+	err = requests.
+		URL(indexURL).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			index, err = readIndexArchive(resp.Body)
+			return err
+		}).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return packageIndex{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch package index %s: %w", indexURL, err)
+	}
+
+	return index, nil
+}
+
+// packageIndex maps package names to their greatest version in the index.
+type packageIndex map[string]string
+
+// readIndexArchive extracts the APKINDEX file - the archive consists of concatenated gzip streams i.e. the signature
+// and the index itself which are read as one tar stream.
+func readIndexArchive(r io.Reader) (packageIndex, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := tar.NewReader(gz)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("archive does not contain an APKINDEX")
+		} else if err != nil {
+			return nil, err
+		}
+
+		if header.Name == "APKINDEX" {
+			return parseIndex(archive)
+		}
+	}
+}
+
+// parseIndex reads the P (package name) and V (version) fields of all records of an APKINDEX.
+func parseIndex(r io.Reader) (packageIndex, error) {
+	var (
+		index   = make(packageIndex)
+		scanner = bufio.NewScanner(r)
+		name    string
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			name = ""
+		case strings.HasPrefix(line, "P:"):
+			name = strings.TrimPrefix(line, "P:")
+		case strings.HasPrefix(line, "V:") && name != "":
+			version := strings.TrimPrefix(line, "V:")
+			if current, ok := index[name]; !ok || CompareVersions(version, current) > 0 {
+				index[name] = version
+			}
+		}
+	}
+
+	return index, scanner.Err()
+}
+
+// branchFor maps the distro qualifier e.g. alpine-3.19.1 or 3.19.1 to the release branch v3.19 - edge is passed through.
+func branchFor(distribution, distro string) (string, bool) {
+	release := strings.TrimPrefix(distro, distribution+"-")
+
+	switch release {
+	case "":
+		return "", false
+	case "edge":
+		return release, true
+	}
+
+	parts := strings.Split(release, ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	for _, part := range parts[:2] {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return "", false
+		}
+	}
+
+	return "v" + parts[0] + "." + parts[1], true
+}
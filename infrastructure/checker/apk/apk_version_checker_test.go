@@ -0,0 +1,151 @@
+package apk_test
+
+import (
+	_ "embed"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/apk"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/v3.19_main_x86_64_APKINDEX.tar.gz
+	mainIndex []byte
+	//go:embed testdata/v3.19_community_x86_64_APKINDEX.tar.gz
+	communityIndex []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package in main repository",
+			args: args{
+				packageUrl: "pkg:apk/alpine/busybox@1.36.1-r15?arch=x86_64&distro=alpine-3.19.1",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "alpine",
+				Name:           "busybox",
+				CurrentVersion: "1.36.1-r15",
+				LatestVersion:  "1.36.1-r20",
+				PackageManager: "apk",
+			},
+		},
+		{
+			name: "Package in community repository with plain distro version",
+			args: args{
+				packageUrl: "pkg:apk/alpine/go@1.21.12-r0?distro=3.19.4",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "alpine",
+				Name:           "go",
+				CurrentVersion: "1.21.12-r0",
+				LatestVersion:  "1.21.13-r1",
+				PackageManager: "apk",
+			},
+		},
+		{
+			name: "Package with suffix",
+			args: args{
+				packageUrl: "pkg:apk/alpine/musl@1.2.4_git20230717-r4?arch=x86_64&distro=alpine-3.19.0",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "alpine",
+				Name:           "musl",
+				CurrentVersion: "1.2.4_git20230717-r4",
+				LatestVersion:  "1.2.4_git20230717-r4",
+				PackageManager: "apk",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:apk/alpine/unknown@1.0-r0?distro=alpine-3.19.1",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "Missing distro qualifier",
+			args: args{
+				packageUrl: "pkg:apk/alpine/busybox@1.36.1-r15",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+		{
+			name: "Unknown distribution",
+			args: args{
+				packageUrl: "pkg:apk/wolfi/busybox@1.36.1-r15?distro=wolfi-20230201",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+
+	mainRule, err := testx.NewSimpleUrlRule("https://dl-cdn.alpinelinux.org/alpine/v3.19/main/x86_64/APKINDEX.tar.gz", mainIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	communityRule, err := testx.NewSimpleUrlRule("https://dl-cdn.alpinelinux.org/alpine/v3.19/community/x86_64/APKINDEX.tar.gz", communityIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var requests atomic.Int32
+
+	// a single checker for all cases to verify indices are shared
+	c := apk.NewChecker(testx.MockHTTPClient(
+		countingRule{ResponseRule: mainRule, requests: &requests},
+		countingRule{ResponseRule: communityRule, requests: &requests},
+	), nil)
+
+	t.Run("cases", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				purl, err := packageurl.FromString(tt.args.packageUrl)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				got, err := c.LatestVersionFor(testx.Context(t), purl)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+				}
+			})
+		}
+	})
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+// countingRule counts the requests served by the wrapped rule.
+type countingRule struct {
+	testx.ResponseRule
+	requests *atomic.Int32
+}
+
+func (c countingRule) Apply(resp *http.Response) {
+	c.requests.Add(1)
+	c.ResponseRule.Apply(resp)
+}
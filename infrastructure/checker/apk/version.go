@@ -0,0 +1,152 @@
+package apk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// suffixOrder ranks the suffixes of apk versions - pre-release suffixes sort before versions without suffix.
+var suffixOrder = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"":      0,
+	"cvs":   1,
+	"svn":   2,
+	"git":   3,
+	"hg":    4,
+	"p":     5,
+}
+
+// version is a parsed apk version: numbers[letter](_suffix[number])*[~hash][-rrevision]
+type version struct {
+	numbers  []string
+	letter   byte
+	suffixes []suffix
+	revision int
+}
+
+type suffix struct {
+	name   string
+	number int
+}
+
+// CompareVersions implements the ordering of apk-tools - versions that can't be parsed are compared as strings.
+func CompareVersions(a, b string) int {
+	aVersion, aOk := parseVersion(a)
+	bVersion, bOk := parseVersion(b)
+
+	if !aOk || !bOk {
+		return strings.Compare(a, b)
+	}
+
+	return aVersion.compare(bVersion)
+}
+
+func parseVersion(raw string) (v version, ok bool) {
+	if base, revision, found := strings.Cut(raw, "-r"); found {
+		parsed, err := strconv.Atoi(revision)
+		if err != nil {
+			return version{}, false
+		}
+
+		raw, v.revision = base, parsed
+	}
+
+	// commit hashes don't take part in the ordering
+	raw, _, _ = strings.Cut(raw, "~")
+
+	raw, suffixes, _ := strings.Cut(raw, "_")
+	if suffixes != "" {
+		for _, s := range strings.Split(suffixes, "_") {
+			name := strings.TrimRight(s, "0123456789")
+			if _, known := suffixOrder[name]; !known || name == "" {
+				return version{}, false
+			}
+
+			var number int
+			if digits := s[len(name):]; digits != "" {
+				number, _ = strconv.Atoi(digits)
+			}
+
+			v.suffixes = append(v.suffixes, suffix{name: name, number: number})
+		}
+	}
+
+	if last := len(raw) - 1; last > 0 && raw[last] >= 'a' && raw[last] <= 'z' {
+		raw, v.letter = raw[:last], raw[last]
+	}
+
+	v.numbers = strings.Split(raw, ".")
+	for _, n := range v.numbers {
+		if n == "" || strings.Trim(n, "0123456789") != "" {
+			return version{}, false
+		}
+	}
+
+	return v, true
+}
+
+func (v version) compare(other version) int {
+	for i := 0; i < len(v.numbers) && i < len(other.numbers); i++ {
+		if c := compareNumber(v.numbers[i], other.numbers[i], i == 0); c != 0 {
+			return c
+		}
+	}
+
+	if c := compareInt(len(v.numbers), len(other.numbers)); c != 0 {
+		return c
+	}
+
+	if c := compareInt(int(v.letter), int(other.letter)); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(v.suffixes) || i < len(other.suffixes); i++ {
+		var a, b suffix
+		if i < len(v.suffixes) {
+			a = v.suffixes[i]
+		}
+
+		if i < len(other.suffixes) {
+			b = other.suffixes[i]
+		}
+
+		if c := compareInt(suffixOrder[a.name], suffixOrder[b.name]); c != 0 {
+			return c
+		}
+
+		if c := compareInt(a.number, b.number); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(v.revision, other.revision)
+}
+
+// compareNumber compares all but the first component as decimal fractions if one of them has a leading zero
+// e.g. 1.01 < 1.1.
+func compareNumber(a, b string, first bool) int {
+	if !first && (strings.HasPrefix(a, "0") || strings.HasPrefix(b, "0")) {
+		return strings.Compare(strings.TrimRight(a, "0"), strings.TrimRight(b, "0"))
+	}
+
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := compareInt(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
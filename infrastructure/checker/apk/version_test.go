@@ -0,0 +1,39 @@
+package apk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.36.1-r15", b: "1.36.1-r15", want: 0},
+		{a: "1.36.1-r15", b: "1.36.1-r20", want: -1},
+		{a: "1.2.4-r2", b: "1.2.4_git20230717-r4", want: -1},
+		{a: "3.1.4-r5", b: "3.1.7-r0", want: -1},
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "1.0", b: "1.0.1", want: -1},
+		{a: "1.0_rc1", b: "1.0", want: -1},
+		{a: "1.0_alpha2", b: "1.0_beta1", want: -1},
+		{a: "1.0_p1", b: "1.0", want: 1},
+		{a: "1.0a", b: "1.0", want: 1},
+		{a: "1.0a", b: "1.0b", want: -1},
+		{a: "1.01", b: "1.1", want: -1},
+		{a: "2024a-r0", b: "2024b-r0", want: -1},
+		{a: "6.4_p20231125-r0", b: "6.4_p20240420-r0", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+var ErrMalformedCacheKey = errors.New("malformed cache key")
+
+var cacheKeySeparator = []byte("/")
+
+const (
+	cacheKeyVersionSeparator    = "@"
+	cacheKeyQualifiersSeparator = "?"
+)
+
+// CacheKeyPrefixFor returns the common prefix of all cache keys of the given package type.
+func CacheKeyPrefixFor(packageType string) []byte {
+	return append([]byte(packageType), cacheKeySeparator...)
+}
+
+// PackageURLFromCacheKey restores the package URL a cache key was derived from.
+// The subpath is not part of the key.
+func PackageURLFromCacheKey(key []byte) (packageurl.PackageURL, error) {
+	typeEnd := bytes.Index(key, cacheKeySeparator)
+	nameStart := bytes.LastIndex(key, cacheKeySeparator)
+
+	if typeEnd <= 0 || typeEnd == nameStart || nameStart == len(key)-1 || !isPackageType(key[:typeEnd]) {
+		return packageurl.PackageURL{}, fmt.Errorf("%w: %s", ErrMalformedCacheKey, key)
+	}
+
+	purl := packageurl.PackageURL{
+		Type:      string(key[:typeEnd]),
+		Namespace: string(key[typeEnd+1 : nameStart]),
+		Name:      string(key[nameStart+1:]),
+	}
+
+	if name, rawQualifiers, found := strings.Cut(purl.Name, cacheKeyQualifiersSeparator); found {
+		values, err := url.ParseQuery(rawQualifiers)
+		if err != nil || name == "" {
+			return packageurl.PackageURL{}, fmt.Errorf("%w: %s", ErrMalformedCacheKey, key)
+		}
+
+		purl.Name = name
+		for _, k := range slices.Sorted(maps.Keys(values)) {
+			purl.Qualifiers = append(purl.Qualifiers, packageurl.Qualifier{Key: k, Value: values.Get(k)})
+		}
+	}
+
+	if name, version, found := strings.Cut(purl.Name, cacheKeyVersionSeparator); found {
+		unescaped, err := url.PathUnescape(version)
+		if err != nil || name == "" {
+			return packageurl.PackageURL{}, fmt.Errorf("%w: %s", ErrMalformedCacheKey, key)
+		}
+
+		purl.Name, purl.Version = name, unescaped
+	}
+
+	return purl, nil
+}
+
+// CacheKeyFor derives the cache key from type, namespace, name, version and qualifiers of the package URL.
+// Results depend on the version e.g. whether the current version is deprecated
+// and on qualifiers e.g. the distribution release of OS packages or the repository_url of private registries.
+func CacheKeyFor(purl packageurl.PackageURL) []byte {
+	name := purl.Name
+	if purl.Version != "" {
+		name += cacheKeyVersionSeparator + url.PathEscape(purl.Version)
+	}
+
+	if len(purl.Qualifiers) > 0 {
+		// url.Values encodes sorted by key and escapes '/' and '@' so the name is still the last segment
+		values := make(url.Values, len(purl.Qualifiers))
+		for _, q := range purl.Qualifiers {
+			values.Set(q.Key, q.Value)
+		}
+
+		name += cacheKeyQualifiersSeparator + values.Encode()
+	}
+
+	return bytes.Join([][]byte{[]byte(purl.Type), []byte(purl.Namespace), []byte(name)}, cacheKeySeparator)
+}
+
+// isPackageType checks the type against the grammar of package URL types
+// - keys of other namespaces in the same store don't resemble package types.
+func isPackageType(raw []byte) bool {
+	for i, c := range raw {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '+' || c == '-'):
+		default:
+			return false
+		}
+	}
+
+	return len(raw) > 0
+}
@@ -0,0 +1,117 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/infrastructure/checker"
+)
+
+func TestPackageURLFromCacheKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		want    packageurl.PackageURL
+		wantErr bool
+	}{
+		{
+			name: "Package without namespace",
+			key:  "npm//is-even-ai",
+			want: packageurl.PackageURL{Type: "npm", Name: "is-even-ai"},
+		},
+		{
+			name: "Package with nested namespace",
+			key:  "golang/github.com/prskr/aucs",
+			want: packageurl.PackageURL{Type: "golang", Namespace: "github.com/prskr", Name: "aucs"},
+		},
+		{
+			name: "Package with version",
+			key:  "npm/@types/node@22.10.1",
+			want: packageurl.PackageURL{Type: "npm", Namespace: "@types", Name: "node", Version: "22.10.1"},
+		},
+		{
+			name: "Package with escaped version",
+			key:  "golang/github.com/prskr/aucs@v0.0.0-20241201%2Fdev",
+			want: packageurl.PackageURL{Type: "golang", Namespace: "github.com/prskr", Name: "aucs", Version: "v0.0.0-20241201/dev"},
+		},
+		{
+			name: "Package with qualifiers",
+			key:  "deb/debian/curl@7.88.1-10?arch=amd64&distro=debian-12",
+			want: packageurl.PackageURL{
+				Type:      "deb",
+				Namespace: "debian",
+				Name:      "curl",
+				Version:   "7.88.1-10",
+				Qualifiers: packageurl.Qualifiers{
+					{Key: "arch", Value: "amd64"},
+					{Key: "distro", Value: "debian-12"},
+				},
+			},
+		},
+		{
+			name: "Package with URL qualifier",
+			key:  "helm//podinfo@6.5.0?repository_url=https%3A%2F%2Fstefanprodan.github.io%2Fpodinfo",
+			want: packageurl.PackageURL{
+				Type:       "helm",
+				Name:       "podinfo",
+				Version:    "6.5.0",
+				Qualifiers: packageurl.Qualifiers{{Key: "repository_url", Value: "https://stefanprodan.github.io/podinfo"}},
+			},
+		},
+		{
+			name:    "Key of another namespace",
+			key:     "!vulnerabilities/npm//is-even-ai@1.0.1",
+			wantErr: true,
+		},
+		{
+			name:    "Missing name",
+			key:     "npm/",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := checker.PackageURLFromCacheKey([]byte(tt.key))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PackageURLFromCacheKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackageURLFromCacheKey_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	packageUrls := []string{
+		"pkg:npm/%40types/node@22.10.1",
+		"pkg:deb/debian/curl@7.88.1-10?arch=amd64&distro=debian-12",
+		"pkg:generic/zlib@1.3.1?vcs_url=git%2Bhttps://github.com/madler/zlib.git%401.3.1",
+		"pkg:conda/numpy@1.26.4?channel=https://conda.anaconda.org/conda-forge&subdir=linux-64",
+	}
+	for _, packageUrl := range packageUrls {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := checker.PackageURLFromCacheKey(checker.CacheKeyFor(want))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, want.ToString(), got.ToString())
+		})
+	}
+}
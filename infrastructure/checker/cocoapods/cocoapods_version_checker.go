@@ -0,0 +1,138 @@
+package cocoapods
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+const (
+	DefaultBaseURL = "https://cdn.cocoapods.org"
+)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL string) Checker {
+	return Checker{
+		Client:  client,
+		BaseURL: baseURL,
+		shards:  memo.New[shard](),
+	}
+}
+
+// Checker looks up the latest version of pods in the version shards of the trunk CDN. Pods are distributed across
+// shards by the first three hex digits of the MD5 sum of their name and every shard is downloaded once and shared by
+// all lookups hence the checker has to be created with NewChecker.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+
+	shards *memo.Cache[shard]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "cocoapods"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	pod := podName(packageUrl)
+	shardName := shardFor(pod)
+
+	versions, err := c.shards.Get(ctx, shardName, func(ctx context.Context) (shard, error) {
+		return c.fetchShard(ctx, shardName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stable, preRelease := semverx.Latest(versions[pod], semver.NewVersion)
+	if stable == "" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoMatchingPackageFound, pod)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:               packageUrl.Namespace,
+		Name:                    packageUrl.Name,
+		CurrentVersion:          packageUrl.Version,
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		PackageManager:          "cocoapods",
+	}, nil
+}
+
+func (c Checker) fetchShard(ctx context.Context, shardName string) (versions shard, err error) {
+	err = requests.
+		URL(httpx.BaseURL(c.BaseURL)).
+		Path(shardName).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			versions, err = parseShard(resp.Body)
+			return err
+		}).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version shard %s: %w", shardName, err)
+	}
+
+	return versions, nil
+}
+
+// podName strips subspecs e.g. pkg:cocoapods/GoogleUtilities/NSData+zlib@7.5.2 is parsed with GoogleUtilities as
+// namespace although the subspec should be passed as subpath.
+func podName(packageUrl packageurl.PackageURL) string {
+	if packageUrl.Namespace != "" {
+		pod, _, _ := strings.Cut(packageUrl.Namespace, "/")
+		return pod
+	}
+
+	return packageUrl.Name
+}
+
+// shardFor returns the name of the shard listing the versions of the given pod e.g. all_pods_versions_d_a_2.txt
+// for Alamofire.
+func shardFor(pod string) string {
+	sum := md5.Sum([]byte(pod))
+	prefix := hex.EncodeToString(sum[:2])[:3]
+
+	return fmt.Sprintf("all_pods_versions_%c_%c_%c.txt", prefix[0], prefix[1], prefix[2])
+}
+
+// shard maps pod names to all their published versions.
+type shard map[string][]string
+
+// parseShard reads lines of pod names followed by their versions e.g. Alamofire/5.9.0/5.9.1/5.10.0.
+func parseShard(r io.Reader) (shard, error) {
+	var (
+		versions = make(shard)
+		scanner  = bufio.NewScanner(r)
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), "/")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+
+		versions[fields[0]] = fields[1:]
+	}
+
+	return versions, scanner.Err()
+}
@@ -0,0 +1,119 @@
+package cocoapods_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/cocoapods"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/all_pods_versions_d_a_2.txt
+	alamofireShard []byte
+	//go:embed testdata/all_pods_versions_0_8_4.txt
+	googleUtilitiesShard []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Pod with pre-release",
+			args: args{
+				packageUrl: "pkg:cocoapods/Alamofire@5.9.0",
+			},
+			want: &ports.PackageInfo{
+				Name:                    "Alamofire",
+				CurrentVersion:          "5.9.0",
+				LatestVersion:           "5.10.1",
+				LatestPreReleaseVersion: "6.0.0-beta.1",
+				PackageManager:          "cocoapods",
+			},
+		},
+		{
+			name: "Subspec",
+			args: args{
+				packageUrl: "pkg:cocoapods/GoogleUtilities@7.5.2#NSData+zlib",
+			},
+			want: &ports.PackageInfo{
+				Name:           "GoogleUtilities",
+				CurrentVersion: "7.5.2",
+				LatestVersion:  "8.0.2",
+				PackageManager: "cocoapods",
+			},
+		},
+		{
+			name: "Subspec in name",
+			args: args{
+				packageUrl: "pkg:cocoapods/GoogleUtilities/NSData%2Bzlib@7.5.2",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "GoogleUtilities",
+				Name:           "NSData+zlib",
+				CurrentVersion: "7.5.2",
+				LatestVersion:  "8.0.2",
+				PackageManager: "cocoapods",
+			},
+		},
+		{
+			name: "Pod names are case sensitive",
+			args: args{
+				packageUrl: "pkg:cocoapods/alamofire@5.9.0",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+	}
+
+	var rules []testx.ResponseRule
+
+	for rawURL, response := range map[string][]byte{
+		"https://cdn.example.com/cocoapods/all_pods_versions_d_a_2.txt": alamofireShard,
+		"https://cdn.example.com/cocoapods/all_pods_versions_0_8_4.txt": googleUtilitiesShard,
+		// alamofire hashes to a different shard than Alamofire
+		"https://cdn.example.com/cocoapods/all_pods_versions_b_8_3.txt": nil,
+	} {
+		rule, err := testx.NewSimpleUrlRule(rawURL, response)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		rules = append(rules, rule)
+	}
+
+	c := cocoapods.NewChecker(testx.MockHTTPClient(rules...), "https://cdn.example.com/cocoapods")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
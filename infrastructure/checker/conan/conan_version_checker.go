@@ -0,0 +1,171 @@
+package conan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+const (
+	DefaultBaseURL = "https://center2.conan.io"
+
+	// repositoryURLQualifier selects another remote for a single package
+	repositoryURLQualifier = "repository_url"
+	userQualifier          = "user"
+	channelQualifier       = "channel"
+
+	// emptyReference is used by the API for references without user and channel
+	emptyReference = "_"
+)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL string) Checker {
+	return Checker{Client: client, BaseURL: baseURL}
+}
+
+// Checker implements the v2 REST API of Conan remotes e.g. ConanCenter or Artifactory. The versions of a recipe are
+// determined by searching the remote for its name - the latest recipe revision of the latest version is reported
+// as well to detect recipe updates without version change.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+	// QualifierHosts restricts the hosts the repository_url qualifier may select
+	QualifierHosts httpx.AllowedHosts
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "conan"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	qualifiers := packageUrl.Qualifiers.Map()
+
+	baseURL := c.BaseURL
+	if repositoryURL := qualifiers[repositoryURLQualifier]; repositoryURL != "" {
+		if err := c.QualifierHosts.Check(repositoryURL); err != nil {
+			return nil, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+		}
+
+		baseURL = repositoryURL
+	}
+
+	user, channel := qualifiers[userQualifier], qualifiers[channelQualifier]
+
+	var search searchResponse
+
+	err := requests.
+		URL(httpx.BaseURL(baseURL)).
+		Path("v2/conans/search").
+		Param("q", packageUrl.Name).
+		Client(c.Client).
+		ToJSON(&search).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", packageUrl.Name, err)
+	}
+
+	versions := search.versionsOf(packageUrl.Name, user, channel)
+
+	stable, preRelease := latestVersions(versions)
+	if stable == "" {
+		return nil, fmt.Errorf("%w: no version of %s", ports.ErrNoMatchingPackageFound, packageUrl.Name)
+	}
+
+	var revision revisionResponse
+
+	err = requests.
+		URL(httpx.BaseURL(baseURL)).
+		Path(path.Join("v2/conans", packageUrl.Name, stable, orEmpty(user), orEmpty(channel), "revisions/latest")).
+		Client(c.Client).
+		ToJSON(&revision).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest revision of %s/%s: %w", packageUrl.Name, stable, err)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:               packageUrl.Namespace,
+		Name:                    packageUrl.Name,
+		CurrentVersion:          packageUrl.Version,
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		LatestRevision:          revision.Revision,
+		PackageManager:          "conan",
+	}, nil
+}
+
+type searchResponse struct {
+	Results []string `json:"results"`
+}
+
+// versionsOf filters the references e.g. zlib/1.3.1 or openssl/3.0.0@user/stable matching the name, user and channel
+// exactly as the search pattern might match other recipes as well.
+func (s searchResponse) versionsOf(name, user, channel string) []string {
+	var versions []string
+
+	for _, ref := range s.Results {
+		ref, _, _ = strings.Cut(ref, "#")
+		ref, userChannel, _ := strings.Cut(ref, "@")
+
+		refName, version, found := strings.Cut(ref, "/")
+		if !found || refName != name {
+			continue
+		}
+
+		refUser, refChannel, _ := strings.Cut(userChannel, "/")
+		if orEmpty(refUser) != orEmpty(user) || orEmpty(refChannel) != orEmpty(channel) {
+			continue
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions
+}
+
+type revisionResponse struct {
+	Revision string `json:"revision"`
+	Time     string `json:"time"`
+}
+
+func orEmpty(s string) string {
+	if s == "" {
+		return emptyReference
+	}
+
+	return s
+}
+
+// latestVersions returns the greatest version and the greatest pre-release if it's newer than the greatest version.
+// Recipes with pre-releases only report the greatest pre-release as latest version.
+func latestVersions(versions []string) (stable, preRelease string) {
+	for _, v := range versions {
+		if _, pre := splitVersion(v); pre == "" {
+			if stable == "" || compareVersions(v, stable) > 0 {
+				stable = v
+			}
+		} else if preRelease == "" || compareVersions(v, preRelease) > 0 {
+			preRelease = v
+		}
+	}
+
+	switch {
+	case stable == "":
+		return preRelease, ""
+	case preRelease != "" && compareVersions(preRelease, stable) > 0:
+		return stable, preRelease
+	default:
+		return stable, ""
+	}
+}
@@ -0,0 +1,150 @@
+package conan_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/conan"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/zlib-search.json
+	zlibSearch []byte
+	//go:embed testdata/zlib-1.3.1-latest-revision.json
+	zlibLatestRevision []byte
+	//go:embed testdata/openssl-search.json
+	opensslSearch []byte
+	//go:embed testdata/openssl-3.3.1-latest-revision.json
+	opensslLatestRevision []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		clientConfig map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Recipe on ConanCenter",
+			args: args{
+				packageUrl: "pkg:conan/zlib@1.2.13?rrev=4e74ebf1361fe6fb60326f473f276eb5",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://center2.conan.io/v2/conans/search?q=zlib":                   zlibSearch,
+					"https://center2.conan.io/v2/conans/zlib/1.3.1/_/_/revisions/latest": zlibLatestRevision,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:                    "zlib",
+				CurrentVersion:          "1.2.13",
+				LatestVersion:           "1.3.1",
+				LatestPreReleaseVersion: "1.3.2-pre",
+				LatestRevision:          "f52e03ae3d251dec704634230cd806a2",
+				PackageManager:          "conan",
+			},
+		},
+		{
+			name: "Recipe with user and channel on other remote",
+			args: args{
+				packageUrl: "pkg:conan/acme/openssl@3.2.0?user=acme&channel=stable&repository_url=https://artifactory.example.com/artifactory/api/conan/conan-local",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://artifactory.example.com/artifactory/api/conan/conan-local/v2/conans/search?q=openssl":                           opensslSearch,
+					"https://artifactory.example.com/artifactory/api/conan/conan-local/v2/conans/openssl/3.3.1/acme/stable/revisions/latest": opensslLatestRevision,
+				},
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "acme",
+				Name:           "openssl",
+				CurrentVersion: "3.2.0",
+				LatestVersion:  "3.3.1",
+				LatestRevision: "9c3d8f0a4e2b6a1d7f5c3b2e1a0d9c8b",
+				PackageManager: "conan",
+			},
+		},
+		{
+			name: "Recipe without user and channel only available with user and channel",
+			args: args{
+				packageUrl: "pkg:conan/openssl@3.2.0",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://center2.conan.io/v2/conans/search?q=openssl": opensslSearch,
+				},
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var rules []testx.ResponseRule
+			for rawURL, response := range tt.fields.clientConfig {
+				rule, err := testx.NewSimpleUrlRule(rawURL, response)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				rules = append(rules, rule)
+			}
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := conan.NewChecker(testx.MockHTTPClient(rules...), conan.DefaultBaseURL).LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	c := conan.NewChecker(testx.MockHTTPClient(), conan.DefaultBaseURL)
+	c.QualifierHosts = httpx.AllowHosts("example.com")
+
+	for _, packageUrl := range []string{
+		"pkg:conan/zlib@1.3.1?repository_url=http://localhost:6379",
+	} {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+			assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+		})
+	}
+}
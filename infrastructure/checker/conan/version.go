@@ -0,0 +1,85 @@
+package conan
+
+import (
+	"strings"
+)
+
+// compareVersions follows the Version model of Conan 2: dot separated items are compared numerically if both are
+// numbers and as strings otherwise, trailing zero items are ignored and pre-releases e.g. 1.2.0-pre sort before the
+// release. Build metadata is ignored.
+func compareVersions(a, b string) int {
+	aMain, aPre := splitVersion(a)
+	bMain, bPre := splitVersion(b)
+
+	if c := compareItems(aMain, bMain); c != 0 {
+		return c
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	default:
+		return compareVersions(aPre, bPre)
+	}
+}
+
+func splitVersion(raw string) (main, pre string) {
+	raw, _, _ = strings.Cut(raw, "+")
+	main, pre, _ = strings.Cut(raw, "-")
+
+	return main, pre
+}
+
+func compareItems(a, b string) int {
+	aItems, bItems := items(a), items(b)
+
+	for i := 0; i < len(aItems) && i < len(bItems); i++ {
+		if c := compareItem(aItems[i], bItems[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(aItems) < len(bItems):
+		return -1
+	case len(aItems) > len(bItems):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// items splits a version at dots and drops trailing zero items i.e. 1.2 equals 1.2.0.
+func items(raw string) []string {
+	parts := strings.Split(strings.ToLower(raw), ".")
+	for len(parts) > 1 && isNumber(parts[len(parts)-1]) && strings.Trim(parts[len(parts)-1], "0") == "" {
+		parts = parts[:len(parts)-1]
+	}
+
+	return parts
+}
+
+func compareItem(a, b string) int {
+	if !isNumber(a) || !isNumber(b) {
+		return strings.Compare(a, b)
+	}
+
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func isNumber(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
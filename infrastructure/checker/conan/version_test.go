@@ -0,0 +1,36 @@
+package conan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.3.1", b: "1.3.1", want: 0},
+		{a: "1.2.13", b: "1.3", want: -1},
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.2.0", b: "1.2.0.1", want: -1},
+		{a: "3.0.0-pre", b: "3.0.0", want: -1},
+		{a: "3.0.0-alpha", b: "3.0.0-beta", want: -1},
+		{a: "1.1.1w", b: "1.1.1v", want: 1},
+		{a: "1.1.1w", b: "3.0.0", want: -1},
+		{a: "cci.20230101", b: "cci.20240101", want: -1},
+		{a: "1.0.0+build1", b: "1.0.0", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, compareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, compareVersions(tt.b, tt.a))
+		})
+	}
+}
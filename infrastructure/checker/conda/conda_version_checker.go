@@ -0,0 +1,175 @@
+package conda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+const (
+	// DefaultChannelAlias is prepended to channel names without explicit URL e.g. conda-forge
+	DefaultChannelAlias = "https://conda.anaconda.org"
+
+	channelQualifier = "channel"
+	subdirQualifier  = "subdir"
+	defaultChannel   = "main"
+	defaultSubdir    = "linux-64"
+	noarchSubdir     = "noarch"
+
+	currentRepodata = "current_repodata.json"
+	fullRepodata    = "repodata.json"
+)
+
+// DefaultChannels are the channels served by repo.anaconda.com instead of the channel alias.
+var DefaultChannels = map[string]string{
+	"main":  "https://repo.anaconda.com/pkgs/main",
+	"r":     "https://repo.anaconda.com/pkgs/r",
+	"msys2": "https://repo.anaconda.com/pkgs/msys2",
+}
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker resolves channel names with the channelAlias unless they're DefaultChannels or configured in channels
+// e.g. to use mirrors.
+func NewChecker(client *http.Client, channelAlias string, channels map[string]string) Checker {
+	merged := make(map[string]string, len(DefaultChannels)+len(channels))
+	for name, channelURL := range DefaultChannels {
+		merged[name] = channelURL
+	}
+
+	for name, channelURL := range channels {
+		merged[name] = channelURL
+	}
+
+	return Checker{
+		Client:       client,
+		ChannelAlias: channelAlias,
+		Channels:     merged,
+		indices:      memo.New[packageIndex](),
+	}
+}
+
+// Checker looks up the latest version of conda packages in the repodata of the channel and subdir taken from the
+// qualifiers - noarch is always searched as well. The current_repodata.json only listing the latest versions is
+// consulted first as it's a fraction of the size of the complete repodata.json. Both are reduced to the latest version
+// per package and shared by all lookups hence the checker has to be created with NewChecker.
+type Checker struct {
+	Client       *http.Client
+	ChannelAlias string
+	Channels     map[string]string
+	// QualifierHosts restricts the hosts channel URLs taken from the channel qualifier may point to
+	QualifierHosts httpx.AllowedHosts
+
+	indices *memo.Cache[packageIndex]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "conda"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	qualifiers := packageUrl.Qualifiers.Map()
+
+	channelURL, err := c.channelURL(qualifiers[channelQualifier])
+	if err != nil {
+		return nil, err
+	}
+
+	subdirs := []string{defaultSubdir, noarchSubdir}
+	if subdir := qualifiers[subdirQualifier]; subdir != "" {
+		subdirs = slices.Compact([]string{subdir, noarchSubdir})
+	}
+
+	for _, repodata := range []string{currentRepodata, fullRepodata} {
+		latest, err := c.latestIn(ctx, channelURL, subdirs, repodata, packageUrl.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if latest != "" {
+			return &ports.PackageInfo{
+				Namespace:      packageUrl.Namespace,
+				Name:           packageUrl.Name,
+				CurrentVersion: packageUrl.Version,
+				LatestVersion:  latest,
+				PackageManager: "conda",
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s in %s", ports.ErrNoMatchingPackageFound, packageUrl.Name, channelURL)
+}
+
+func (c Checker) latestIn(ctx context.Context, channelURL string, subdirs []string, repodata, name string) (latest string, err error) {
+	for _, subdir := range subdirs {
+		indexURL := httpx.BaseURL(channelURL) + path.Join(subdir, repodata)
+
+		index, err := c.indices.Get(ctx, indexURL, func(ctx context.Context) (packageIndex, error) {
+			return c.fetchIndex(ctx, indexURL)
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if version, ok := index[name]; ok && (latest == "" || compareVersions(version, latest) > 0) {
+			latest = version
+		}
+	}
+
+	return latest, nil
+}
+
+// channelURL resolves channel names e.g. conda-forge and passes through channel URLs.
+func (c Checker) channelURL(channel string) (string, error) {
+	if channel == "" {
+		channel = defaultChannel
+	}
+
+	if strings.Contains(channel, "://") {
+		if err := c.QualifierHosts.Check(channel); err != nil {
+			return "", fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+		}
+
+		return channel, nil
+	}
+
+	if channelURL, ok := c.Channels[channel]; ok {
+		return channelURL, nil
+	}
+
+	return httpx.BaseURL(c.ChannelAlias) + channel, nil
+}
+
+// fetchIndex treats missing repodata e.g. channels without current_repodata.json as empty.
+func (c Checker) fetchIndex(ctx context.Context, indexURL string) (index packageIndex, err error) {
+	err = requests.
+		URL(indexURL).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			index, err = parseRepodata(resp.Body)
+			return err
+		}).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return packageIndex{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repodata %s: %w", indexURL, err)
+	}
+
+	return index, nil
+}
@@ -0,0 +1,194 @@
+package conda_test
+
+import (
+	_ "embed"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/conda"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/main-linux-64-current_repodata.json
+	mainLinuxCurrentRepodata []byte
+	//go:embed testdata/main-noarch-current_repodata.json
+	mainNoarchCurrentRepodata []byte
+	//go:embed testdata/main-linux-64-repodata.json
+	mainLinuxRepodata []byte
+	//go:embed testdata/conda-forge-linux-64-repodata.json
+	condaForgeLinuxRepodata []byte
+	//go:embed testdata/empty-repodata.json
+	emptyRepodata []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package in current repodata",
+			args: args{
+				packageUrl: "pkg:conda/numpy@1.26.4?build=py312h2809609_0&channel=main&subdir=linux-64&type=tar.bz2",
+			},
+			want: &ports.PackageInfo{
+				Name:           "numpy",
+				CurrentVersion: "1.26.4",
+				LatestVersion:  "2.0.1",
+				PackageManager: "conda",
+			},
+		},
+		{
+			name: "Noarch package in default channel",
+			args: args{
+				packageUrl: "pkg:conda/tzdata@2024a?subdir=noarch",
+			},
+			want: &ports.PackageInfo{
+				Name:           "tzdata",
+				CurrentVersion: "2024a",
+				LatestVersion:  "2024b",
+				PackageManager: "conda",
+			},
+		},
+		{
+			name: "Channel without current repodata",
+			args: args{
+				packageUrl: "pkg:conda/openssl@3.3.2?channel=conda-forge&subdir=linux-64",
+			},
+			want: &ports.PackageInfo{
+				Name:           "openssl",
+				CurrentVersion: "3.3.2",
+				LatestVersion:  "3.4.0",
+				PackageManager: "conda",
+			},
+		},
+		{
+			name: "Channel URL",
+			args: args{
+				packageUrl: "pkg:conda/openssl@1.1.1w?channel=https://conda.anaconda.org/conda-forge&subdir=linux-64",
+			},
+			want: &ports.PackageInfo{
+				Name:           "openssl",
+				CurrentVersion: "1.1.1w",
+				LatestVersion:  "3.4.0",
+				PackageManager: "conda",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:conda/unknown@1.0?channel=main&subdir=linux-64",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+	}
+
+	var (
+		requests atomic.Int32
+		rules    []testx.ResponseRule
+	)
+
+	for _, r := range []struct {
+		url      string
+		response []byte
+		status   int
+	}{
+		{url: "https://repo.anaconda.com/pkgs/main/linux-64/current_repodata.json", response: mainLinuxCurrentRepodata},
+		{url: "https://repo.anaconda.com/pkgs/main/noarch/current_repodata.json", response: mainNoarchCurrentRepodata},
+		{url: "https://repo.anaconda.com/pkgs/main/linux-64/repodata.json", response: mainLinuxRepodata},
+		{url: "https://repo.anaconda.com/pkgs/main/noarch/repodata.json", response: emptyRepodata},
+		{url: "https://conda.anaconda.org/conda-forge/linux-64/current_repodata.json", status: http.StatusNotFound},
+		{url: "https://conda.anaconda.org/conda-forge/noarch/current_repodata.json", status: http.StatusNotFound},
+		{url: "https://conda.anaconda.org/conda-forge/linux-64/repodata.json", response: condaForgeLinuxRepodata},
+		{url: "https://conda.anaconda.org/conda-forge/noarch/repodata.json", response: emptyRepodata},
+	} {
+		rule, err := testx.NewSimpleUrlRule(r.url, r.response)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if r.status != 0 {
+			rule.StatusCode = r.status
+		}
+
+		rules = append(rules, countingRule{ResponseRule: rule, requests: &requests})
+	}
+
+	// a single checker for all cases to verify the repodata is shared
+	c := conda.NewChecker(testx.MockHTTPClient(rules...), conda.DefaultChannelAlias, nil)
+
+	t.Run("cases", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				purl, err := packageurl.FromString(tt.args.packageUrl)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				got, err := c.LatestVersionFor(testx.Context(t), purl)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+				}
+			})
+		}
+	})
+
+	// every repodata document is fetched once although the conda-forge channel is referenced by name and URL
+	assert.Equal(t, int32(8), requests.Load())
+}
+
+// countingRule counts the requests served by the wrapped rule.
+type countingRule struct {
+	testx.ResponseRule
+	requests *atomic.Int32
+}
+
+func (c countingRule) Apply(resp *http.Response) {
+	c.requests.Add(1)
+	c.ResponseRule.Apply(resp)
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	c := conda.NewChecker(testx.MockHTTPClient(), conda.DefaultChannelAlias, nil)
+	c.QualifierHosts = httpx.AllowHosts("example.com")
+
+	for _, packageUrl := range []string{
+		"pkg:conda/openssl@1.1.1w?channel=http://10.0.0.1/conda-forge&subdir=linux-64",
+	} {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+			assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package conda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrMalformedRepodata = errors.New("malformed repodata")
+
+// packageIndex maps package names to their greatest version in the repodata.
+type packageIndex map[string]string
+
+type repodataRecord struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// parseRepodata decodes the records of the packages and packages.conda objects one by one as the repodata of large
+// channels e.g. conda-forge takes hundreds of megabytes - the records of .tar.bz2 and .conda packages are merged.
+func parseRepodata(r io.Reader) (packageIndex, error) {
+	var (
+		index   = make(packageIndex)
+		decoder = json.NewDecoder(r)
+	)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "packages", "packages.conda":
+			if err := index.addRecords(decoder); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", key, err)
+			}
+		default:
+			// e.g. info or removed
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// addRecords decodes an object of records by filename - null is treated as empty object.
+func (i packageIndex) addRecords(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+
+	if token != json.Delim('{') {
+		return fmt.Errorf("%w: expected object but got %v", ErrMalformedRepodata, token)
+	}
+
+	for decoder.More() {
+		// the filename e.g. numpy-2.0.1-py312h2809609_0.tar.bz2
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+
+		var record repodataRecord
+		if err := decoder.Decode(&record); err != nil {
+			return err
+		}
+
+		if current, ok := i[record.Name]; !ok || compareVersions(record.Version, current) > 0 {
+			i[record.Name] = record.Version
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("%w: expected %v but got %v", ErrMalformedRepodata, delim, token)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package conda
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseRepodata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		repodata string
+		want     packageIndex
+		wantErr  error
+	}{
+		{
+			name: "Merges packages and packages.conda",
+			repodata: `{
+				"info": {"subdir": "linux-64"},
+				"packages": {
+					"numpy-1.26.4-py312h2809609_0.tar.bz2": {"name": "numpy", "version": "1.26.4", "depends": ["python >=3.12"]},
+					"zlib-1.2.13-h5eee18b_1.tar.bz2": {"name": "zlib", "version": "1.2.13"}
+				},
+				"packages.conda": {
+					"numpy-2.0.1-py312h2809609_1.conda": {"name": "numpy", "version": "2.0.1"}
+				},
+				"removed": ["zlib-1.2.11-h7f8727e_4.tar.bz2"],
+				"repodata_version": 1
+			}`,
+			want: packageIndex{"numpy": "2.0.1", "zlib": "1.2.13"},
+		},
+		{
+			name:     "Null packages",
+			repodata: `{"packages": null, "packages.conda": {"zlib-1.3.1-h5eee18b_0.conda": {"name": "zlib", "version": "1.3.1"}}}`,
+			want:     packageIndex{"zlib": "1.3.1"},
+		},
+		{
+			name:     "Packages aren't an object",
+			repodata: `{"packages": []}`,
+			wantErr:  ErrMalformedRepodata,
+		},
+		{
+			name:     "Not an object",
+			repodata: `[]`,
+			wantErr:  ErrMalformedRepodata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseRepodata(strings.NewReader(tt.repodata))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
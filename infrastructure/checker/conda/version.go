@@ -0,0 +1,201 @@
+package conda
+
+import (
+	"strings"
+)
+
+// element is a single part of a version component e.g. 1, rc and 2 of 1rc2.
+type element struct {
+	number string
+	text   string
+	// isNumber distinguishes the fill value 0 from empty strings
+	isNumber bool
+}
+
+var zero = element{number: "0", isNumber: true}
+
+// version follows the VersionOrder of conda: [epoch!]components[+local].
+type version struct {
+	epoch string
+	main  [][]element
+	local [][]element
+}
+
+// compareVersions implements the ordering of conda - versions that can't be parsed are compared as strings.
+func compareVersions(a, b string) int {
+	aVersion, aOk := parseVersion(a)
+	bVersion, bOk := parseVersion(b)
+
+	if !aOk || !bOk {
+		return strings.Compare(a, b)
+	}
+
+	return aVersion.compare(bVersion)
+}
+
+func parseVersion(raw string) (v version, ok bool) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+
+	v.epoch = "0"
+	if epoch, rest, found := strings.Cut(raw, "!"); found {
+		if epoch == "" || strings.Trim(epoch, "0123456789") != "" {
+			return version{}, false
+		}
+
+		v.epoch, raw = epoch, rest
+	}
+
+	raw, local, _ := strings.Cut(raw, "+")
+
+	if v.main, ok = parseComponents(raw); !ok {
+		return version{}, false
+	}
+
+	if local != "" {
+		if v.local, ok = parseComponents(local); !ok {
+			return version{}, false
+		}
+	}
+
+	return v, true
+}
+
+// parseComponents splits versions at . and _ into components consisting of runs of digits and letters.
+// A trailing underscore is kept as letter element to order e.g. 1.1_ between 1.1dev1 and 1.1a1.
+func parseComponents(raw string) ([][]element, bool) {
+	raw = strings.ReplaceAll(raw, "-", "_")
+
+	trailingUnderscore := strings.HasSuffix(raw, "_")
+	raw = strings.TrimSuffix(raw, "_")
+
+	if raw == "" {
+		return nil, false
+	}
+
+	parts := strings.FieldsFunc(raw, func(r rune) bool { return r == '.' || r == '_' })
+	components := make([][]element, 0, len(parts))
+
+	for _, part := range parts {
+		var component []element
+
+		for part != "" {
+			isDigit := part[0] >= '0' && part[0] <= '9'
+
+			end := strings.IndexFunc(part, func(r rune) bool {
+				return (r >= '0' && r <= '9') != isDigit
+			})
+			if end < 0 {
+				end = len(part)
+			}
+
+			run := part[:end]
+			part = part[end:]
+
+			switch {
+			case isDigit:
+				component = append(component, element{number: strings.TrimLeft(run, "0"), isNumber: true})
+			case strings.Trim(run, "abcdefghijklmnopqrstuvwxyz*") != "":
+				return nil, false
+			case run == "dev":
+				// dev releases sort before all other letters
+				component = append(component, element{text: "DEV"})
+			default:
+				component = append(component, element{text: run})
+			}
+		}
+
+		// components starting with letters are treated as if they had a leading 0 e.g. 1.a1 == 1.0a1
+		if !component[0].isNumber {
+			component = append([]element{zero}, component...)
+		}
+
+		components = append(components, component)
+	}
+
+	if trailingUnderscore {
+		last := len(components) - 1
+		components[last] = append(components[last], element{text: "_"})
+	}
+
+	return components, true
+}
+
+func (v version) compare(other version) int {
+	if c := compareNumbers(v.epoch, other.epoch); c != 0 {
+		return c
+	}
+
+	if c := compareComponents(v.main, other.main); c != 0 {
+		return c
+	}
+
+	return compareComponents(v.local, other.local)
+}
+
+// compareComponents pads the shorter list of components and the shorter component with 0.
+func compareComponents(a, b [][]element) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aComponent, bComponent []element
+		if i < len(a) {
+			aComponent = a[i]
+		}
+
+		if i < len(b) {
+			bComponent = b[i]
+		}
+
+		for j := 0; j < len(aComponent) || j < len(bComponent); j++ {
+			aElement, bElement := zero, zero
+			if j < len(aComponent) {
+				aElement = aComponent[j]
+			}
+
+			if j < len(bComponent) {
+				bElement = bComponent[j]
+			}
+
+			if c := compareElements(aElement, bElement); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return 0
+}
+
+// compareElements orders post releases after everything else and letters before numbers.
+func compareElements(a, b element) int {
+	switch aPost, bPost := a.text == "post", b.text == "post"; {
+	case aPost && bPost:
+		return 0
+	case aPost:
+		return 1
+	case bPost:
+		return -1
+	}
+
+	switch {
+	case a.isNumber && b.isNumber:
+		return compareNumbers(a.number, b.number)
+	case a.isNumber:
+		return 1
+	case b.isNumber:
+		return -1
+	default:
+		return strings.Compare(a.text, b.text)
+	}
+}
+
+// compareNumbers compares decimal numbers of arbitrary length without leading zeros.
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
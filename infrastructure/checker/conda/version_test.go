@@ -0,0 +1,66 @@
+package conda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compareVersions_ordering(t *testing.T) {
+	t.Parallel()
+
+	// taken from the documentation of conda's VersionOrder
+	ordered := []string{
+		"0.4",
+		"0.4.1.rc",
+		"0.4.1",
+		"0.5a1",
+		"0.5b3",
+		"0.5C1",
+		"0.5",
+		"0.9.6",
+		"0.960923",
+		"1.0",
+		"1.1dev1",
+		"1.1_",
+		"1.1a1",
+		"1.1.0dev1",
+		"1.1.a1",
+		"1.1.0rc1",
+		"1.1.0",
+		"1.1.0post1",
+		"1.1post1",
+		"1996.07.12",
+		"1!0.4.1",
+		"1!3.1.1.6",
+		"2!0.4.1",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		assert.Equal(t, -1, compareVersions(ordered[i], ordered[i+1]), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, compareVersions(ordered[i+1], ordered[i]), "%s > %s", ordered[i+1], ordered[i])
+	}
+}
+
+func Test_compareVersions_equal(t *testing.T) {
+	t.Parallel()
+
+	for _, pair := range [][2]string{
+		{"0.4", "0.4.0"},
+		{"0.4.1.rc", "0.4.1.RC"},
+		{"1.1.0dev1", "1.1.dev1"},
+		{"1.1.0", "1.1"},
+		{"1.1.0post1", "1.1.post1"},
+		{"1.2.3-1", "1.2.3_1"},
+	} {
+		assert.Equal(t, 0, compareVersions(pair[0], pair[1]), "%s == %s", pair[0], pair[1])
+	}
+}
+
+func Test_compareVersions_local(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, -1, compareVersions("1.2.3+1", "1.2.3+2"))
+	assert.Equal(t, 1, compareVersions("1.2.4", "1.2.3+2"))
+	assert.Equal(t, 1, compareVersions("2024.10.1", "2024.2.0"))
+}
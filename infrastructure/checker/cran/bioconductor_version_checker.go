@@ -0,0 +1,79 @@
+package cran
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+const (
+	DefaultBioconductorBaseURL = "https://bioconductor.org"
+
+	// releaseQualifier pins the Bioconductor release e.g. 3.18 - the current release is used if it's missing
+	releaseQualifier = "release"
+	currentRelease   = "release"
+)
+
+// bioconductorRepositories are the repositories of every Bioconductor release relative to the release.
+var bioconductorRepositories = []string{"bioc", "data/annotation", "data/experiment", "workflows"}
+
+var _ ports.UpdateChecker = (*BioconductorChecker)(nil)
+
+func NewBioconductorChecker(client *http.Client, baseURL string) BioconductorChecker {
+	return BioconductorChecker{
+		Client:  client,
+		BaseURL: baseURL,
+		indices: newIndices(client),
+	}
+}
+
+// BioconductorChecker looks up the latest version of Bioconductor packages in the repositories of the release taken
+// from the release qualifier. Packages are only updated within a release as every release targets a specific version of
+// R. The indices are downloaded once and shared by all lookups hence the checker has to be created with
+// NewBioconductorChecker.
+type BioconductorChecker struct {
+	Client  *http.Client
+	BaseURL string
+
+	indices indices
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (BioconductorChecker) SupportedPackageType() string {
+	return "bioconductor"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c BioconductorChecker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	release := packageUrl.Qualifiers.Map()[releaseQualifier]
+	if release == "" {
+		release = currentRelease
+	}
+
+	for _, repository := range bioconductorRepositories {
+		repositoryURL := httpx.BaseURL(c.BaseURL) + path.Join("packages", release, repository)
+
+		latest, err := c.indices.latestIn(ctx, repositoryURL, packageUrl.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if latest != "" {
+			return &ports.PackageInfo{
+				Namespace:      packageUrl.Namespace,
+				Name:           packageUrl.Name,
+				CurrentVersion: packageUrl.Version,
+				LatestVersion:  latest,
+				PackageManager: "bioconductor",
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s in Bioconductor %s", ports.ErrNoMatchingPackageFound, packageUrl.Name, release)
+}
@@ -0,0 +1,114 @@
+package cran_test
+
+import (
+	_ "embed"
+	"net/http"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/cran"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/bioc_3.18_PACKAGES.gz
+	biocPackages []byte
+	//go:embed testdata/bioc_3.18_annotation_PACKAGES.gz
+	biocAnnotationPackages []byte
+)
+
+func TestBioconductorChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Software package of release",
+			args: args{
+				packageUrl: "pkg:bioconductor/DESeq2@1.42.0?release=3.18",
+			},
+			want: &ports.PackageInfo{
+				Name:           "DESeq2",
+				CurrentVersion: "1.42.0",
+				LatestVersion:  "1.42.1",
+				PackageManager: "bioconductor",
+			},
+		},
+		{
+			name: "Annotation package",
+			args: args{
+				packageUrl: "pkg:bioconductor/org.Hs.eg.db@3.18.0?release=3.18",
+			},
+			want: &ports.PackageInfo{
+				Name:           "org.Hs.eg.db",
+				CurrentVersion: "3.18.0",
+				LatestVersion:  "3.18.0",
+				PackageManager: "bioconductor",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:bioconductor/unknown@1.0.0?release=3.18",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+	}
+
+	var rules []testx.ResponseRule
+
+	for _, r := range []struct {
+		url      string
+		response []byte
+		status   int
+	}{
+		{url: "https://bioconductor.org/packages/3.18/bioc/src/contrib/PACKAGES.gz", response: biocPackages},
+		{url: "https://bioconductor.org/packages/3.18/data/annotation/src/contrib/PACKAGES.gz", response: biocAnnotationPackages},
+		{url: "https://bioconductor.org/packages/3.18/data/experiment/src/contrib/PACKAGES.gz", status: http.StatusNotFound},
+		{url: "https://bioconductor.org/packages/3.18/workflows/src/contrib/PACKAGES.gz", status: http.StatusNotFound},
+	} {
+		rule, err := testx.NewSimpleUrlRule(r.url, r.response)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if r.status != 0 {
+			rule.StatusCode = r.status
+		}
+
+		rules = append(rules, rule)
+	}
+
+	c := cran.NewBioconductorChecker(testx.MockHTTPClient(rules...), cran.DefaultBioconductorBaseURL)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cran
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+const DefaultBaseURL = "https://cloud.r-project.org"
+
+// maxArchiveListingSize limits how much of the HTML listing of the archive is read
+const maxArchiveListingSize = 4 << 20
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL string) Checker {
+	return Checker{
+		Client:  client,
+		BaseURL: baseURL,
+		indices: newIndices(client),
+	}
+}
+
+// Checker looks up the latest version of R packages in the PACKAGES index of CRAN or one of its mirrors.
+// Packages removed from CRAN are looked up in the archive and reported as deprecated. The index is downloaded once and
+// shared by all lookups hence the checker has to be created with NewChecker.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+
+	indices indices
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "cran"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info := &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		PackageManager: "cran",
+	}
+
+	latest, err := c.indices.latestIn(ctx, c.BaseURL, packageUrl.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest != "" {
+		info.LatestVersion = latest
+		return info, nil
+	}
+
+	archived, err := c.latestArchived(ctx, packageUrl.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	info.LatestVersion = archived
+	info.Deprecated = true
+	info.DeprecationMessage = "archived on CRAN"
+
+	return info, nil
+}
+
+// latestArchived reads the tarballs e.g. ggplot2_3.4.0.tar.gz from the directory listing of the package in the archive.
+func (c Checker) latestArchived(ctx context.Context, name string) (latest string, err error) {
+	tarball := regexp.MustCompile(`href="` + regexp.QuoteMeta(name) + `_([0-9.\-]+)\.tar\.gz"`)
+
+	err = requests.
+		URL(httpx.BaseURL(c.BaseURL)).
+		Path(path.Join("src/contrib/Archive", name) + "/").
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			listing, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveListingSize))
+			if err != nil {
+				return err
+			}
+
+			for _, match := range tarball.FindAllSubmatch(listing, -1) {
+				if version := string(match[1]); latest == "" || CompareVersions(version, latest) > 0 {
+					latest = version
+				}
+			}
+
+			return nil
+		}).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return "", fmt.Errorf("%w: %s", ports.ErrNoMatchingPackageFound, name)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to fetch archive of %s: %w", name, err)
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("%w: no archived version of %s", ports.ErrNoMatchingPackageFound, name)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,127 @@
+package cran_test
+
+import (
+	_ "embed"
+	"net/http"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/cran"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/cran_PACKAGES.gz
+	cranPackages []byte
+	//go:embed testdata/rgdal_archive.html
+	rgdalArchive []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package in index",
+			args: args{
+				packageUrl: "pkg:cran/ggplot2@3.4.4",
+			},
+			want: &ports.PackageInfo{
+				Name:           "ggplot2",
+				CurrentVersion: "3.4.4",
+				LatestVersion:  "3.5.1",
+				PackageManager: "cran",
+			},
+		},
+		{
+			name: "Dashed version",
+			args: args{
+				packageUrl: "pkg:cran/zoo@1.8-9",
+			},
+			want: &ports.PackageInfo{
+				Name:           "zoo",
+				CurrentVersion: "1.8-9",
+				LatestVersion:  "1.8-12",
+				PackageManager: "cran",
+			},
+		},
+		{
+			name: "Archived package",
+			args: args{
+				packageUrl: "pkg:cran/rgdal@1.5-32",
+			},
+			want: &ports.PackageInfo{
+				Name:               "rgdal",
+				CurrentVersion:     "1.5-32",
+				LatestVersion:      "1.6-7",
+				PackageManager:     "cran",
+				Deprecated:         true,
+				DeprecationMessage: "archived on CRAN",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:cran/unknown@1.0",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+	}
+
+	var rules []testx.ResponseRule
+
+	for _, r := range []struct {
+		url      string
+		response []byte
+		status   int
+	}{
+		{url: "https://cran.example.com/src/contrib/PACKAGES.gz", response: cranPackages},
+		{url: "https://cran.example.com/src/contrib/Archive/rgdal/", response: rgdalArchive},
+		{url: "https://cran.example.com/src/contrib/Archive/unknown/", status: http.StatusNotFound},
+	} {
+		rule, err := testx.NewSimpleUrlRule(r.url, r.response)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if r.status != 0 {
+			rule.StatusCode = r.status
+		}
+
+		rules = append(rules, rule)
+	}
+
+	c := cran.NewChecker(testx.MockHTTPClient(rules...), "https://cran.example.com")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
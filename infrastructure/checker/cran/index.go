@@ -0,0 +1,114 @@
+package cran
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+// packageIndex maps package names to their greatest version in the PACKAGES index of a repository.
+type packageIndex map[string]string
+
+// indices downloads the PACKAGES index of every repository once.
+type indices struct {
+	client *http.Client
+	cache  *memo.Cache[packageIndex]
+}
+
+func newIndices(client *http.Client) indices {
+	return indices{
+		client: client,
+		cache:  memo.New[packageIndex](),
+	}
+}
+
+// latestIn returns the version of the package in the repository e.g. https://cloud.r-project.org - it is empty if the
+// repository doesn't contain the package.
+func (i indices) latestIn(ctx context.Context, repositoryURL, name string) (string, error) {
+	indexURL := httpx.BaseURL(repositoryURL) + "src/contrib/PACKAGES.gz"
+
+	index, err := i.cache.Get(ctx, indexURL, func(ctx context.Context) (packageIndex, error) {
+		return i.fetch(ctx, indexURL)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return index[name], nil
+}
+
+// fetch treats missing indices e.g. of repositories of not yet published Bioconductor releases as empty.
+func (i indices) fetch(ctx context.Context, indexURL string) (index packageIndex, err error) {
+	err = requests.
+		URL(indexURL).
+		Client(i.client).
+		Handle(func(resp *http.Response) error {
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			index, err = parsePackages(gz)
+			return err
+		}).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return packageIndex{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch package index %s: %w", indexURL, err)
+	}
+
+	return index, nil
+}
+
+// parsePackages reads the Package and Version fields of the DCF records of a PACKAGES index - continuation lines
+// of other fields e.g. Depends are skipped.
+func parsePackages(r io.Reader) (packageIndex, error) {
+	var (
+		index   = make(packageIndex)
+		scanner = bufio.NewScanner(r)
+		name    string
+		version string
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	record := func() {
+		if name != "" && version != "" {
+			if current, ok := index[name]; !ok || CompareVersions(version, current) > 0 {
+				index[name] = version
+			}
+		}
+
+		name, version = "", ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			record()
+		case strings.HasPrefix(line, "Package:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "Package:"))
+		case strings.HasPrefix(line, "Version:"):
+			version = strings.TrimSpace(strings.TrimPrefix(line, "Version:"))
+		}
+	}
+
+	record()
+
+	return index, scanner.Err()
+}
@@ -0,0 +1,58 @@
+package cran
+
+import "strings"
+
+// CompareVersions follows package_version of R: versions consist of numbers separated by . or - e.g. 1.0-12 and are
+// compared component-wise - a version is lower than versions it is a prefix of. Versions that can't be parsed are
+// compared as strings.
+func CompareVersions(a, b string) int {
+	aComponents, aOk := parseVersion(a)
+	bComponents, bOk := parseVersion(b)
+
+	if !aOk || !bOk {
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(aComponents) && i < len(bComponents); i++ {
+		if c := compareNumbers(aComponents[i], bComponents[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(len(aComponents), len(bComponents))
+}
+
+func parseVersion(raw string) ([]string, bool) {
+	components := strings.FieldsFunc(strings.TrimSpace(raw), func(r rune) bool { return r == '.' || r == '-' })
+	if len(components) == 0 {
+		return nil, false
+	}
+
+	for _, c := range components {
+		if strings.Trim(c, "0123456789") != "" {
+			return nil, false
+		}
+	}
+
+	return components, true
+}
+
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := compareInt(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,33 @@
+package cran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "3.5.1", b: "3.5.1", want: 0},
+		{a: "1.0-2", b: "1.0.2", want: 0},
+		{a: "1.0-9", b: "1.0-12", want: -1},
+		{a: "0.9.8", b: "1.0", want: -1},
+		{a: "1.0", b: "1.0-1", want: -1},
+		{a: "1.10.0", b: "1.9.9", want: 1},
+		{a: "1.42.0", b: "1.42.1", want: -1},
+		{a: "2024.1.1", b: "2023.12.31", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
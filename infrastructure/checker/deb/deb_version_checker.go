@@ -0,0 +1,253 @@
+package deb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+const (
+	distroQualifier = "distro"
+	archQualifier   = "arch"
+	defaultArch     = "amd64"
+
+	// securitySuffix marks the security suite of a release as well as the mirror serving it e.g. debian-security
+	securitySuffix = "-security"
+)
+
+// DefaultMirrors are the archives of the supported distributions indexed by the package URL namespace.
+var DefaultMirrors = map[string]string{
+	"debian": "https://deb.debian.org/debian",
+	"ubuntu": "http://archive.ubuntu.com/ubuntu",
+}
+
+// DefaultSecurityMirrors are the archives serving the security suites of distributions that don't publish them in their
+// regular archive - ubuntu ships the security suite in the regular archive.
+var DefaultSecurityMirrors = map[string]string{
+	"debian": "https://security.debian.org/debian-security",
+}
+
+// codenames maps release versions as used by the distro qualifier e.g. debian-12 to their codenames.
+var codenames = map[string]map[string]string{
+	"debian": {
+		"10": "buster",
+		"11": "bullseye",
+		"12": "bookworm",
+		"13": "trixie",
+	},
+	"ubuntu": {
+		"18.04": "bionic",
+		"20.04": "focal",
+		"22.04": "jammy",
+		"24.04": "noble",
+		"24.10": "oracular",
+	},
+}
+
+// components that are searched for packages - ubuntu ships many packages pulled into images in universe.
+var components = map[string][]string{
+	"debian": {"main"},
+	"ubuntu": {"main", "universe"},
+}
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker uses the DefaultMirrors and DefaultSecurityMirrors unless they're overridden by mirrors.
+// Mirrors of security suites are configured by the distribution suffixed with -security e.g. debian-security.
+func NewChecker(client *http.Client, mirrors map[string]string) Checker {
+	merged := maps.Clone(DefaultMirrors)
+	securityMirrors := maps.Clone(DefaultSecurityMirrors)
+
+	for distribution, mirror := range mirrors {
+		if distribution, ok := strings.CutSuffix(distribution, securitySuffix); ok {
+			securityMirrors[distribution] = mirror
+			continue
+		}
+
+		merged[distribution] = mirror
+	}
+
+	return Checker{
+		Client:          client,
+		Mirrors:         merged,
+		SecurityMirrors: securityMirrors,
+		indices:         memo.New[packageIndex](),
+	}
+}
+
+// Checker looks up the latest version of Debian and Ubuntu packages in the Packages indices of the release
+// taken from the distro qualifier - including the -updates and -security suites. Indices are downloaded once and shared
+// by all lookups hence the checker has to be created with NewChecker.
+type Checker struct {
+	Client  *http.Client
+	Mirrors map[string]string
+	// SecurityMirrors serve the -security suites - distributions without one are looked up in their mirror
+	SecurityMirrors map[string]string
+
+	indices *memo.Cache[packageIndex]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "deb"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	mirror, ok := c.Mirrors[packageUrl.Namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w: no mirror for distribution %q", ports.ErrNoCheckerForPackageType, packageUrl.Namespace)
+	}
+
+	qualifiers := packageUrl.Qualifiers.Map()
+
+	codename, ok := codenameFor(packageUrl.Namespace, qualifiers[distroQualifier])
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown release %q of %s", ports.ErrNoCheckerForPackageType, qualifiers[distroQualifier], packageUrl.Namespace)
+	}
+
+	arch := qualifiers[archQualifier]
+	if arch == "" || arch == "all" {
+		arch = defaultArch
+	}
+
+	securityMirror, ok := c.SecurityMirrors[packageUrl.Namespace]
+	if !ok {
+		securityMirror = mirror
+	}
+
+	suites := []struct{ mirror, name string }{
+		{mirror: mirror, name: codename},
+		{mirror: mirror, name: codename + "-updates"},
+		{mirror: securityMirror, name: codename + securitySuffix},
+	}
+
+	var latest string
+
+	for _, suite := range suites {
+		for _, component := range components[packageUrl.Namespace] {
+			indexURL := httpx.BaseURL(suite.mirror) + path.Join("dists", suite.name, component, "binary-"+arch, "Packages.gz")
+
+			index, err := c.indices.Get(ctx, indexURL, func(ctx context.Context) (packageIndex, error) {
+				return c.fetchIndex(ctx, indexURL)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if version, ok := index[packageUrl.Name]; ok && (latest == "" || CompareVersions(version, latest) > 0) {
+				latest = version
+			}
+		}
+	}
+
+	if latest == "" {
+		return nil, fmt.Errorf("%w: %s in %s", ports.ErrNoMatchingPackageFound, packageUrl.Name, codename)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latest,
+		PackageManager: "deb",
+	}, nil
+}
+
+// fetchIndex treats missing indices e.g. of a component that doesn't exist for a release as empty.
+func (c Checker) fetchIndex(ctx context.Context, indexURL string) (index packageIndex, err error) {
+	err = requests.
+		URL(indexURL).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			index, err = parsePackages(gz)
+			return err
+		}).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return packageIndex{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch package index %s: %w", indexURL, err)
+	}
+
+	return index, nil
+}
+
+// packageIndex maps package names to their greatest version in the index.
+type packageIndex map[string]string
+
+// parsePackages reads the Package and Version fields of all stanzas of a Packages index.
+func parsePackages(r io.Reader) (packageIndex, error) {
+	var (
+		index   = make(packageIndex)
+		scanner = bufio.NewScanner(r)
+		name    string
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			name = ""
+		case strings.HasPrefix(line, "Package:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "Package:"))
+		case strings.HasPrefix(line, "Version:") && name != "":
+			version := strings.TrimSpace(strings.TrimPrefix(line, "Version:"))
+			if current, ok := index[name]; !ok || CompareVersions(version, current) > 0 {
+				index[name] = version
+			}
+		}
+	}
+
+	return index, scanner.Err()
+}
+
+// codenameFor accepts versions prefixed by the distribution e.g. debian-12, plain versions and codenames.
+func codenameFor(distribution, distro string) (string, bool) {
+	release := strings.TrimPrefix(distro, distribution+"-")
+	if release == "" {
+		return "", false
+	}
+
+	if codename, ok := codenames[distribution][release]; ok {
+		return codename, true
+	}
+
+	// point releases e.g. debian-12.7 belong to the major release
+	if major, _, found := strings.Cut(release, "."); found && distribution == "debian" {
+		if codename, ok := codenames[distribution][major]; ok {
+			return codename, true
+		}
+	}
+
+	// codenames e.g. bookworm or releases not known yet
+	if strings.Trim(release, "abcdefghijklmnopqrstuvwxyz") == "" {
+		return release, true
+	}
+
+	return "", false
+}
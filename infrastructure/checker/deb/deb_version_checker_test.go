@@ -0,0 +1,186 @@
+package deb_test
+
+import (
+	_ "embed"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/deb"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/bookworm_main_amd64_Packages.gz
+	bookwormMainPackages []byte
+	//go:embed testdata/bookworm-updates_main_amd64_Packages.gz
+	bookwormUpdatesMainPackages []byte
+	//go:embed testdata/bookworm-security_main_amd64_Packages.gz
+	bookwormSecurityMainPackages []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package updated in updates suite",
+			args: args{
+				packageUrl: "pkg:deb/debian/libssl3@3.0.14-1~deb12u2?arch=amd64&distro=debian-12",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "debian",
+				Name:           "libssl3",
+				CurrentVersion: "3.0.14-1~deb12u2",
+				LatestVersion:  "3.0.15-1~deb12u1",
+				PackageManager: "deb",
+			},
+		},
+		{
+			name: "Package updated in security suite",
+			args: args{
+				packageUrl: "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "debian",
+				Name:           "curl",
+				CurrentVersion: "7.88.1-10+deb12u5",
+				LatestVersion:  "7.88.1-10+deb12u8",
+				PackageManager: "deb",
+			},
+		},
+		{
+			name: "Architecture independent package with codename",
+			args: args{
+				packageUrl: "pkg:deb/debian/tzdata@2024a-0%2Bdeb12u1?arch=all&distro=bookworm",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "debian",
+				Name:           "tzdata",
+				CurrentVersion: "2024a-0+deb12u1",
+				LatestVersion:  "2024b-0+deb12u1",
+				PackageManager: "deb",
+			},
+		},
+		{
+			name: "Package with epoch in point release",
+			args: args{
+				packageUrl: "pkg:deb/debian/vim@2:9.0.1378-2?distro=debian-12.7",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "debian",
+				Name:           "vim",
+				CurrentVersion: "2:9.0.1378-2",
+				LatestVersion:  "2:9.0.1378-2",
+				PackageManager: "deb",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:deb/debian/unknown@1.0?distro=debian-12",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "Missing distro qualifier",
+			args: args{
+				packageUrl: "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+		{
+			name: "Unknown distribution",
+			args: args{
+				packageUrl: "pkg:deb/kali/curl@8.0.0?distro=kali-2024",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+
+	mainRule, err := testx.NewSimpleUrlRule("https://deb.debian.org/debian/dists/bookworm/main/binary-amd64/Packages.gz", bookwormMainPackages)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updatesRule, err := testx.NewSimpleUrlRule("https://deb.debian.org/debian/dists/bookworm-updates/main/binary-amd64/Packages.gz", bookwormUpdatesMainPackages)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	securityRule, err := testx.NewSimpleUrlRule("https://security.debian.org/debian-security/dists/bookworm-security/main/binary-amd64/Packages.gz", bookwormSecurityMainPackages)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var requests atomic.Int32
+
+	// a single checker for all cases to verify indices are shared
+	c := deb.NewChecker(testx.MockHTTPClient(
+		countingRule{ResponseRule: mainRule, requests: &requests},
+		countingRule{ResponseRule: updatesRule, requests: &requests},
+		countingRule{ResponseRule: securityRule, requests: &requests},
+	), nil)
+
+	t.Run("cases", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				purl, err := packageurl.FromString(tt.args.packageUrl)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				got, err := c.LatestVersionFor(testx.Context(t), purl)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+				}
+			})
+		}
+	})
+
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+// countingRule counts the requests served by the wrapped rule.
+type countingRule struct {
+	testx.ResponseRule
+	requests *atomic.Int32
+}
+
+func (c countingRule) Apply(resp *http.Response) {
+	c.requests.Add(1)
+	c.ResponseRule.Apply(resp)
+}
+
+func TestNewChecker_Mirrors(t *testing.T) {
+	t.Parallel()
+
+	c := deb.NewChecker(http.DefaultClient, map[string]string{
+		"debian":          "https://mirror.example.com/debian",
+		"debian-security": "https://mirror.example.com/debian-security",
+	})
+
+	assert.Equal(t, "https://mirror.example.com/debian", c.Mirrors["debian"])
+	assert.Equal(t, "http://archive.ubuntu.com/ubuntu", c.Mirrors["ubuntu"])
+	assert.Equal(t, map[string]string{"debian": "https://mirror.example.com/debian-security"}, c.SecurityMirrors)
+	assert.NotContains(t, c.Mirrors, "debian-security")
+}
@@ -0,0 +1,118 @@
+package deb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions implements the dpkg version ordering: epoch, upstream version and Debian revision
+// are compared in that order, each with the algorithm of dpkg's verrevcmp.
+func CompareVersions(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitVersion(a)
+	bEpoch, bUpstream, bRevision := splitVersion(b)
+
+	if aEpoch != bEpoch {
+		if aEpoch < bEpoch {
+			return -1
+		}
+
+		return 1
+	}
+
+	if c := compareFragment(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+
+	return compareFragment(aRevision, bRevision)
+}
+
+// splitVersion splits [epoch:]upstream_version[-debian_revision].
+func splitVersion(version string) (epoch int, upstream, revision string) {
+	if e, rest, found := strings.Cut(version, ":"); found {
+		if parsed, err := strconv.Atoi(e); err == nil {
+			epoch, version = parsed, rest
+		}
+	}
+
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		return epoch, version[:i], version[i+1:]
+	}
+
+	return epoch, version, ""
+}
+
+// compareFragment alternately compares non-digit and digit runs.
+func compareFragment(a, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigit(a[0])) || (b != "" && !isDigit(b[0])) {
+			ac, bc := order(a), order(b)
+			if ac != bc {
+				if ac < bc {
+					return -1
+				}
+
+				return 1
+			}
+
+			a, b = a[1:], b[1:]
+		}
+
+		var aNum, bNum string
+		aNum, a = leadingDigits(a)
+		bNum, b = leadingDigits(b)
+
+		if c := compareNumeric(aNum, bNum); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// order of a character in non-digit runs - ~ sorts before everything, even the end of the run,
+// letters sort before non-letters.
+func order(s string) int {
+	if s == "" || isDigit(s[0]) {
+		return 0
+	}
+
+	switch c := s[0]; {
+	case c == '~':
+		return -1
+	case isLetter(c):
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+func leadingDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// compareNumeric compares arbitrary long digit runs without overflowing.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
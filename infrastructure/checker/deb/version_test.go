@@ -0,0 +1,38 @@
+package deb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0", b: "1.0", want: 0},
+		{a: "1.0", b: "1.1", want: -1},
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "1:1.0", b: "2.0", want: 1},
+		{a: "1.0~rc1", b: "1.0", want: -1},
+		{a: "1.0~~", b: "1.0~", want: -1},
+		{a: "1.0", b: "1.0-1", want: -1},
+		{a: "1.0a", b: "1.0+", want: -1},
+		{a: "7.88.1-10+deb12u5", b: "7.88.1-10+deb12u8", want: -1},
+		{a: "3.0.14-1~deb12u2", b: "3.0.14-1", want: -1},
+		{a: "2024a-0+deb12u1", b: "2024b-0+deb12u1", want: -1},
+		{a: "1.0-1", b: "1.0-1.1", want: -1},
+		{a: "20240101000000000000000001", b: "20240101000000000000000002", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
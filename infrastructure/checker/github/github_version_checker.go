@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/gittag"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+const (
+	DefaultBaseURL = "https://api.github.com"
+
+	tagsPageSize = "100"
+	// maxTagPages limits the number of requests for repositories with excessive numbers of tags
+	maxTagPages = 20
+)
+
+var ErrTooManyTags = errors.New("too many tags")
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL, token string) Checker {
+	return Checker{Client: client, BaseURL: baseURL, Token: token}
+}
+
+// Checker resolves the latest tag of GitHub repositories e.g. GitHub Actions via the REST API.
+// BaseURL is the API URL of GitHub Enterprise Server instances e.g. https://github.example.com/api/v3.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+	// Token is optional but raises the rate limit of the API
+	Token string
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "github"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	tags, err := c.listTags(ctx, packageUrl.Namespace, packageUrl.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := gittag.LatestTag(packageUrl.Version, tags)
+	if latest == "" {
+		return nil, fmt.Errorf("%w: no version tag in %s/%s", ports.ErrNoMatchingPackageFound, packageUrl.Namespace, packageUrl.Name)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latest,
+		PackageManager: "github",
+	}, nil
+}
+
+// listTags follows the pagination links of the tags endpoint.
+func (c Checker) listTags(ctx context.Context, owner, repo string) ([]string, error) {
+	var (
+		tags    []string
+		nextURL string
+	)
+
+	for page := 0; page == 0 || nextURL != ""; page++ {
+		// reporting the latest of a truncated tags list would be misleading
+		if page == maxTagPages {
+			return nil, fmt.Errorf("%w: %s/%s has more than %d pages of tags", ErrTooManyTags, owner, repo, maxTagPages)
+		}
+
+		var (
+			result []githubTag
+			rb     *requests.Builder
+		)
+
+		if nextURL == "" {
+			rb = requests.
+				URL(httpx.BaseURL(c.BaseURL)).
+				Path(path.Join("repos", owner, repo, "tags")).
+				Param("per_page", tagsPageSize)
+		} else {
+			rb = requests.URL(nextURL)
+		}
+
+		err := rb.
+			Client(c.Client).
+			Accept("application/vnd.github+json").
+			HeaderOptional("Authorization", bearer(c.Token)).
+			Handle(func(resp *http.Response) error {
+				nextURL = httpx.NextLink(resp.Header.Get("Link"))
+				return json.NewDecoder(resp.Body).Decode(&result)
+			}).
+			Fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range result {
+			tags = append(tags, t.Name)
+		}
+	}
+
+	return tags, nil
+}
+
+type githubTag struct {
+	Name string `json:"name"`
+}
+
+func bearer(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	return "Bearer " + token
+}
@@ -0,0 +1,168 @@
+package github_test
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/github"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/checkout_tags_page1.json
+	checkoutTagsPage1Response []byte
+	//go:embed testdata/checkout_tags_page2.json
+	checkoutTagsPage2Response []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		baseURL string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr bool
+	}{
+		{
+			name: "Action pinned to major version",
+			args: args{
+				packageUrl: "pkg:github/actions/checkout@v3",
+			},
+			fields: fields{
+				baseURL: github.DefaultBaseURL,
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "actions",
+				Name:           "checkout",
+				CurrentVersion: "v3",
+				LatestVersion:  "v4",
+				PackageManager: "github",
+			},
+		},
+		{
+			name: "Action pinned to full version on GitHub Enterprise Server",
+			args: args{
+				packageUrl: "pkg:github/actions/checkout@v3.6.0",
+			},
+			fields: fields{
+				baseURL: "https://github.example.com/api/v3",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "actions",
+				Name:           "checkout",
+				CurrentVersion: "v3.6.0",
+				LatestVersion:  "v4.2.2",
+				PackageManager: "github",
+			},
+		},
+		{
+			name: "Unknown repository",
+			args: args{
+				packageUrl: "pkg:github/actions/unknown@v1",
+			},
+			fields: fields{
+				baseURL: github.DefaultBaseURL,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			firstPage, err := testx.NewSimpleUrlRule(tt.fields.baseURL+"/repos/actions/checkout/tags?per_page=100", checkoutTagsPage1Response)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			secondPage, err := testx.NewSimpleUrlRule(tt.fields.baseURL+"/repositories/197814629/tags?per_page=100&page=2", checkoutTagsPage2Response)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c := github.NewChecker(testx.MockHTTPClient(
+				linkRule{
+					SimpleUrlRule: firstPage,
+					link:          `<` + tt.fields.baseURL + `/repositories/197814629/tags?per_page=100&page=2>; rel="next", <` + tt.fields.baseURL + `/repositories/197814629/tags?per_page=100&page=2>; rel="last"`,
+				},
+				secondPage,
+			), tt.fields.baseURL, "")
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Checker.LatestVersionFor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// linkRule adds the pagination header GitHub sends for all but the last page.
+type linkRule struct {
+	testx.SimpleUrlRule
+	link string
+}
+
+func (r linkRule) Apply(resp *http.Response) {
+	r.SimpleUrlRule.Apply(resp)
+	resp.Header = http.Header{"Link": []string{r.link}}
+}
+
+func TestChecker_LatestVersionFor_TooManyTags(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	c := github.NewChecker(testx.MockHTTPClient(endlessTagsRule{requests: &requests}), github.DefaultBaseURL, "")
+
+	purl, err := packageurl.FromString("pkg:github/acme/tags-galore@v1.0.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = c.LatestVersionFor(testx.Context(t), purl)
+	assert.ErrorIs(t, err, github.ErrTooManyTags)
+	assert.Equal(t, int32(20), requests.Load())
+}
+
+// endlessTagsRule links every page of the tags of acme/tags-galore to another page.
+type endlessTagsRule struct {
+	requests *atomic.Int32
+}
+
+func (endlessTagsRule) Matches(req *http.Request) bool {
+	return req.URL.Host == "api.github.com" &&
+		(req.URL.Path == "/repos/acme/tags-galore/tags" || req.URL.Path == "/repositories/42/tags")
+}
+
+func (e endlessTagsRule) Apply(resp *http.Response) {
+	page := e.requests.Add(1)
+
+	resp.StatusCode = http.StatusOK
+	resp.Header = http.Header{"Link": {fmt.Sprintf(`<https://api.github.com/repositories/42/tags?per_page=100&page=%d>; rel="next"`, page+1)}}
+	resp.Body = io.NopCloser(strings.NewReader(fmt.Sprintf(`[{"name":"v1.0.%d"}]`, page)))
+}
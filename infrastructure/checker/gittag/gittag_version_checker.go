@@ -0,0 +1,90 @@
+package gittag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+// vcsURLQualifier points to the repository of generic packages e.g. git+https://github.com/madler/zlib.git@v1.3.1
+const vcsURLQualifier = "vcs_url"
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client) Checker {
+	return Checker{Client: client}
+}
+
+// Checker resolves the latest tag of generic packages from the git repository referenced by their vcs_url qualifier.
+type Checker struct {
+	Client *http.Client
+	// QualifierHosts restricts the hosts the vcs_url qualifier may select
+	QualifierHosts httpx.AllowedHosts
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "generic"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	vcsURL := packageUrl.Qualifiers.Map()[vcsURLQualifier]
+	if vcsURL == "" {
+		return nil, fmt.Errorf("%w: generic package without %s qualifier", ports.ErrNoCheckerForPackageType, vcsURLQualifier)
+	}
+
+	repositoryURL, err := repositoryURLFor(vcsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.QualifierHosts.Check(repositoryURL); err != nil {
+		return nil, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+	}
+
+	tags, err := ListRemoteTags(ctx, c.Client, repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := LatestTag(packageUrl.Version, tags)
+	if latest == "" {
+		return nil, fmt.Errorf("%w: no version tag in %s", ports.ErrNoMatchingPackageFound, repositoryURL)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latest,
+		PackageManager: "git",
+	}, nil
+}
+
+// repositoryURLFor strips the VCS tool and revision off a vcs_url - only repositories served via HTTP are supported.
+func repositoryURLFor(vcsURL string) (string, error) {
+	tool, repositoryURL, found := strings.Cut(vcsURL, "+")
+	if !found {
+		tool, repositoryURL = "git", vcsURL
+	}
+
+	if tool != "git" || (!strings.HasPrefix(repositoryURL, "https://") && !strings.HasPrefix(repositoryURL, "http://")) {
+		return "", fmt.Errorf("%w: unsupported %s %s", ports.ErrNoCheckerForPackageType, vcsURLQualifier, vcsURL)
+	}
+
+	// the revision follows the last path segment e.g. .../zlib.git@v1.3.1
+	if slash := strings.LastIndex(repositoryURL, "/"); slash >= 0 {
+		if at := strings.Index(repositoryURL[slash:], "@"); at >= 0 {
+			repositoryURL = repositoryURL[:slash+at]
+		}
+	}
+
+	return repositoryURL, nil
+}
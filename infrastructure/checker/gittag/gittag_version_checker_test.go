@@ -0,0 +1,142 @@
+package gittag_test
+
+import (
+	_ "embed"
+	"errors"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/gittag"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+//go:embed testdata/zlib_info_refs
+var zlibInfoRefsResponse []byte
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		clientConfig map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Vendored library with revision in vcs_url",
+			args: args{
+				packageUrl: "pkg:generic/zlib@v1.2.13?vcs_url=git%2Bhttps://github.com/madler/zlib.git%40v1.2.13",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://github.com/madler/zlib.git/info/refs?service=git-upload-pack": zlibInfoRefsResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "zlib",
+				CurrentVersion: "v1.2.13",
+				LatestVersion:  "v1.3.1",
+				PackageManager: "git",
+			},
+		},
+		{
+			name: "Tag granularity is preserved",
+			args: args{
+				packageUrl: "pkg:generic/zlib@v1.2?vcs_url=https://github.com/madler/zlib.git",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://github.com/madler/zlib.git/info/refs?service=git-upload-pack": zlibInfoRefsResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "zlib",
+				CurrentVersion: "v1.2",
+				LatestVersion:  "v1.3",
+				PackageManager: "git",
+			},
+		},
+		{
+			name: "Missing vcs_url",
+			args: args{
+				packageUrl: "pkg:generic/openssl@3.0.7",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+		{
+			name: "Unsupported VCS",
+			args: args{
+				packageUrl: "pkg:generic/sqlite@3.46.0?vcs_url=fossil%2Bhttps://sqlite.org/src",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			responseRules := make([]testx.ResponseRule, 0, len(tt.fields.clientConfig))
+			for rawUrl, resp := range tt.fields.clientConfig {
+				respRule, err := testx.NewSimpleUrlRule(rawUrl, resp)
+				if !assert.NoError(t, err) {
+					return
+				}
+				responseRules = append(responseRules, respRule)
+			}
+
+			c := gittag.NewChecker(testx.MockHTTPClient(responseRules...))
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Checker.LatestVersionFor() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	c := gittag.NewChecker(testx.MockHTTPClient())
+	c.QualifierHosts = httpx.AllowHosts("github.com")
+
+	for _, packageUrl := range []string{
+		"pkg:generic/zlib@1.3.1?vcs_url=git%2Bhttp://169.254.169.254/latest/meta-data",
+		"pkg:generic/zlib@1.3.1?vcs_url=git%2Bhttps://git.example.com/madler/zlib.git%40v1.3.1",
+	} {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+			assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package gittag
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// LatestTag returns the greatest stable semver tag with the same granularity as the current tag
+// e.g. v4 instead of v4.1.2 for v3 as actions are commonly pinned to their major version tag.
+// If the current tag isn't a version or no tag has the same granularity the greatest of all stable tags is returned.
+// It returns an empty string if none of the tags is a stable version.
+func LatestTag(current string, tags []string) string {
+	var (
+		currentShape, currentIsVersion = shapeOf(current)
+		latest, latestMatching         *semver.Version
+		latestTag, latestMatchingTag   string
+	)
+
+	for _, tag := range tags {
+		shape, ok := shapeOf(tag)
+		if !ok {
+			continue
+		}
+
+		parsed, err := semver.NewVersion(tag)
+		if err != nil || parsed.Prerelease() != "" {
+			continue
+		}
+
+		if latest == nil || parsed.GreaterThan(latest) {
+			latest, latestTag = parsed, tag
+		}
+
+		if currentIsVersion && shape == currentShape && (latestMatching == nil || parsed.GreaterThan(latestMatching)) {
+			latestMatching, latestMatchingTag = parsed, tag
+		}
+	}
+
+	if latestMatching != nil {
+		return latestMatchingTag
+	}
+
+	return latestTag
+}
+
+// shape describes the notation of a version tag to compare only tags of the same notation.
+type shape struct {
+	prefixed   bool
+	components int
+}
+
+// shapeOf accepts tags consisting of up to three numeric components optionally prefixed by v and followed by
+// a pre-release or build suffix.
+func shapeOf(tag string) (shape, bool) {
+	version, prefixed := strings.CutPrefix(tag, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	components := strings.Split(version, ".")
+	if len(components) > 3 {
+		return shape{}, false
+	}
+
+	for _, c := range components {
+		if c == "" || strings.Trim(c, "0123456789") != "" {
+			return shape{}, false
+		}
+	}
+
+	return shape{prefixed: prefixed, components: len(components)}, true
+}
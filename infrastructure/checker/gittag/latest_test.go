@@ -0,0 +1,42 @@
+package gittag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/infrastructure/checker/gittag"
+)
+
+func TestLatestTag(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{"v2", "v3", "v3.1.0", "v4", "v4.0.1", "v4.1", "v4.1.2", "v5.0.0-beta.1", "v5-beta", "latest", "1.9.0"}
+
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{name: "Major version tag", current: "v3", want: "v4"},
+		{name: "Minor version tag", current: "v4.0", want: "v4.1"},
+		{name: "Full version tag", current: "v3.1.0", want: "v4.1.2"},
+		{name: "Unprefixed version", current: "1.0.0", want: "1.9.0"},
+		{name: "No tag of same granularity", current: "v4.1.2.1", want: "v4.1.2"},
+		{name: "Commit hash", current: "b4ffde65f46336ab88eb53be808477a3936bae11", want: "v4.1.2"},
+		{name: "No current version", current: "", want: "v4.1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, gittag.LatestTag(tt.current, tags))
+		})
+	}
+}
+
+func TestLatestTag_NoStableVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, gittag.LatestTag("v1", []string{"nightly", "v2.0.0-rc.1"}))
+}
@@ -0,0 +1,79 @@
+package gittag
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+)
+
+const (
+	tagRefPrefix = "refs/tags/"
+	peeledSuffix = "^{}"
+)
+
+// ListRemoteTags lists all tags of a git repository served via the smart HTTP protocol
+// by requesting the ref advertisement like git ls-remote does.
+func ListRemoteTags(ctx context.Context, client *http.Client, repositoryURL string) (tags []string, err error) {
+	err = requests.
+		URL(strings.TrimSuffix(repositoryURL, "/")+"/info/refs").
+		Param("service", "git-upload-pack").
+		UserAgent("git/aucs").
+		Client(client).
+		Handle(func(resp *http.Response) error {
+			tags, err = parseRefAdvertisement(resp.Body)
+			return err
+		}).
+		Fetch(ctx)
+
+	return tags, err
+}
+
+// parseRefAdvertisement reads the tag names from the pkt-line encoded ref advertisement.
+// Annotated tags are advertised twice - the peeled entry is skipped.
+func parseRefAdvertisement(r io.Reader) ([]string, error) {
+	var (
+		reader = bufio.NewReader(r)
+		tags   []string
+		length = make([]byte, 4)
+	)
+
+	for {
+		if _, err := io.ReadFull(reader, length); err != nil {
+			if err == io.EOF {
+				return tags, nil
+			}
+
+			return nil, fmt.Errorf("failed to read pkt-line length: %w", err)
+		}
+
+		n, err := strconv.ParseUint(string(length), 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("malformed pkt-line length %q: %w", length, err)
+		}
+
+		// flush packets separate the service announcement from the refs
+		if n < 4 {
+			continue
+		}
+
+		payload := make([]byte, n-4)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return nil, fmt.Errorf("failed to read pkt-line: %w", err)
+		}
+
+		line, _, _ := strings.Cut(strings.TrimSuffix(string(payload), "\n"), "\x00")
+
+		_, ref, found := strings.Cut(line, " ")
+		if !found || !strings.HasPrefix(ref, tagRefPrefix) || strings.HasSuffix(ref, peeledSuffix) {
+			continue
+		}
+
+		tags = append(tags, strings.TrimPrefix(ref, tagRefPrefix))
+	}
+}
@@ -0,0 +1,145 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+	"gopkg.in/yaml.v3"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+const (
+	repositoryURLQualifier = "repository_url"
+	ociScheme              = "oci://"
+)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client) Checker {
+	return Checker{
+		Client:  client,
+		indices: memo.New[chartIndex](),
+	}
+}
+
+// Checker looks up the latest version of Helm charts in the chart repository or OCI registry taken from the
+// repository_url qualifier. Chart repository indices are downloaded once and shared by all lookups hence the checker
+// has to be created with NewChecker.
+type Checker struct {
+	Client *http.Client
+	// QualifierHosts restricts the hosts the repository_url qualifier may select
+	QualifierHosts httpx.AllowedHosts
+
+	indices *memo.Cache[chartIndex]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "helm"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	repositoryURL := packageUrl.Qualifiers.Map()[repositoryURLQualifier]
+	if repositoryURL == "" {
+		return nil, fmt.Errorf("%w: %s has no %s qualifier", ports.ErrNoCheckerForPackageType, packageUrl.Name, repositoryURLQualifier)
+	}
+
+	var (
+		info *ports.PackageInfo
+		err  error
+	)
+
+	// registries are accessed via HTTPS
+	if err := c.QualifierHosts.Check(strings.Replace(repositoryURL, ociScheme, "https://", 1)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+	}
+
+	if strings.HasPrefix(repositoryURL, ociScheme) {
+		info, err = c.latestFromRegistry(ctx, strings.TrimPrefix(repositoryURL, ociScheme), packageUrl.Name)
+	} else {
+		info, err = c.latestFromIndex(ctx, repositoryURL, packageUrl.Name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	info.Namespace = packageUrl.Namespace
+	info.Name = packageUrl.Name
+	info.CurrentVersion = packageUrl.Version
+	info.PackageManager = "helm"
+
+	return info, nil
+}
+
+func (c Checker) latestFromIndex(ctx context.Context, repositoryURL, chart string) (*ports.PackageInfo, error) {
+	indexURL := httpx.BaseURL(repositoryURL) + "index.yaml"
+
+	index, err := c.indices.Get(ctx, indexURL, func(ctx context.Context) (chartIndex, error) {
+		return c.fetchIndex(ctx, indexURL)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		versions = make(map[string]chartVersion, len(index.Entries[chart]))
+		tags     = make([]string, 0, len(index.Entries[chart]))
+	)
+
+	for _, v := range index.Entries[chart] {
+		versions[v.Version] = v
+		tags = append(tags, v.Version)
+	}
+
+	stable, preRelease := semverx.Latest(tags, semver.NewVersion)
+	if stable == "" {
+		return nil, fmt.Errorf("%w: no version of %s in %s", ports.ErrNoMatchingPackageFound, chart, repositoryURL)
+	}
+
+	latest := versions[stable]
+
+	return &ports.PackageInfo{
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		LatestAppVersion:        latest.AppVersion,
+		Deprecated:              latest.Deprecated,
+	}, nil
+}
+
+func (c Checker) fetchIndex(ctx context.Context, indexURL string) (index chartIndex, err error) {
+	err = requests.
+		URL(indexURL).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			return yaml.NewDecoder(resp.Body).Decode(&index)
+		}).
+		Fetch(ctx)
+	if err != nil {
+		return chartIndex{}, fmt.Errorf("failed to fetch chart repository index %s: %w", indexURL, err)
+	}
+
+	return index, nil
+}
+
+// chartIndex is the subset of the index.yaml of a chart repository required to determine the latest versions.
+type chartIndex struct {
+	Entries map[string][]chartVersion `yaml:"entries"`
+}
+
+// chartVersion is the subset of the Chart.yaml metadata in the index.yaml and the config of OCI charts.
+type chartVersion struct {
+	Version    string `yaml:"version" json:"version"`
+	AppVersion string `yaml:"appVersion" json:"appVersion"`
+	Deprecated bool   `yaml:"deprecated" json:"deprecated"`
+}
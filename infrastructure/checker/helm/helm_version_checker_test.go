@@ -0,0 +1,268 @@
+package helm_test
+
+import (
+	"bytes"
+	_ "embed"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/helm"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/index.yaml
+	bitnamiIndex []byte
+	//go:embed testdata/podinfo-tags-1.json
+	podinfoTagsPage1 []byte
+	//go:embed testdata/podinfo-tags-2.json
+	podinfoTagsPage2 []byte
+	//go:embed testdata/podinfo-manifest.json
+	podinfoManifest []byte
+	//go:embed testdata/podinfo-config.json
+	podinfoConfig []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Chart in chart repository",
+			args: args{
+				packageUrl: "pkg:helm/nginx@18.2.4?repository_url=https://charts.bitnami.com/bitnami",
+			},
+			want: &ports.PackageInfo{
+				Name:                    "nginx",
+				CurrentVersion:          "18.2.4",
+				LatestVersion:           "18.2.6",
+				LatestPreReleaseVersion: "19.0.0-rc.1",
+				LatestAppVersion:        "1.27.3",
+				PackageManager:          "helm",
+			},
+		},
+		{
+			name: "Deprecated chart",
+			args: args{
+				packageUrl: "pkg:helm/kubeapps@17.0.3?repository_url=https://charts.bitnami.com/bitnami/",
+			},
+			want: &ports.PackageInfo{
+				Name:             "kubeapps",
+				CurrentVersion:   "17.0.3",
+				LatestVersion:    "17.1.1",
+				LatestAppVersion: "2.12.1",
+				PackageManager:   "helm",
+				Deprecated:       true,
+			},
+		},
+		{
+			name: "Unknown chart",
+			args: args{
+				packageUrl: "pkg:helm/unknown@1.0.0?repository_url=https://charts.bitnami.com/bitnami",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "OCI chart",
+			args: args{
+				packageUrl: "pkg:helm/podinfo@6.6.3?repository_url=oci://ghcr.io/stefanprodan/charts",
+			},
+			want: &ports.PackageInfo{
+				Name:                    "podinfo",
+				CurrentVersion:          "6.6.3",
+				LatestVersion:           "6.7.1",
+				LatestPreReleaseVersion: "6.8.0-rc.1",
+				LatestAppVersion:        "6.7.1",
+				PackageManager:          "helm",
+			},
+		},
+		{
+			name: "Missing repository URL",
+			args: args{
+				packageUrl: "pkg:helm/nginx@18.2.4",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+
+	indexRule, err := testx.NewSimpleUrlRule("https://charts.bitnami.com/bitnami/index.yaml", bitnamiIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var indexRequests atomic.Int32
+
+	// a single checker for all cases to verify the index is shared
+	c := helm.NewChecker(testx.MockHTTPClient(append(
+		registryRules(t),
+		countingRule{ResponseRule: indexRule, requests: &indexRequests},
+	)...))
+
+	t.Run("cases", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				purl, err := packageurl.FromString(tt.args.packageUrl)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				got, err := c.LatestVersionFor(testx.Context(t), purl)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+				}
+			})
+		}
+	})
+
+	assert.Equal(t, int32(1), indexRequests.Load())
+}
+
+// registryRules mock ghcr.io challenging anonymous requests and paginating the tags list.
+func registryRules(t *testing.T) []testx.ResponseRule {
+	t.Helper()
+
+	const repository = "https://ghcr.io/v2/stefanprodan/charts/podinfo/"
+
+	rules := []testx.ResponseRule{challengeRule{
+		host:      "ghcr.io",
+		challenge: `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:stefanprodan/charts/podinfo:pull"`,
+	}}
+
+	for _, r := range []struct {
+		url      string
+		response []byte
+		header   http.Header
+	}{
+		{url: "https://ghcr.io/token?scope=repository:stefanprodan/charts/podinfo:pull&service=ghcr.io", response: []byte(`{"token":"djE6c3RlZmFucHJvZGFu"}`)},
+		{
+			url:      repository + "tags/list?n=1000",
+			response: podinfoTagsPage1,
+			header:   http.Header{"Link": {`</v2/stefanprodan/charts/podinfo/tags/list?last=6.7.1&n=3>; rel="next"`}},
+		},
+		{url: repository + "tags/list?last=6.7.1&n=3", response: podinfoTagsPage2},
+		{url: repository + "manifests/6.7.1", response: podinfoManifest},
+		{url: repository + "blobs/sha256:0e1f3ac6d6a0b2a1f6a1d7e8c9b0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8", response: podinfoConfig},
+	} {
+		rule, err := testx.NewSimpleUrlRule(r.url, r.response)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		rules = append(rules, headerRule{SimpleUrlRule: rule, header: r.header})
+	}
+
+	return rules
+}
+
+// challengeRule rejects requests to the registry API without bearer token.
+type challengeRule struct {
+	host      string
+	challenge string
+}
+
+func (c challengeRule) Matches(req *http.Request) bool {
+	return req.URL.Host == c.host && req.URL.Path != "/token" && req.Header.Get("Authorization") != "Bearer djE6c3RlZmFucHJvZGFu"
+}
+
+func (c challengeRule) Apply(resp *http.Response) {
+	resp.StatusCode = http.StatusUnauthorized
+	resp.Header = http.Header{"Www-Authenticate": {c.challenge}}
+	resp.Body = io.NopCloser(bytes.NewReader(nil))
+}
+
+// headerRule adds response headers to a testx.SimpleUrlRule.
+type headerRule struct {
+	testx.SimpleUrlRule
+	header http.Header
+}
+
+func (h headerRule) Apply(resp *http.Response) {
+	h.SimpleUrlRule.Apply(resp)
+	resp.Header = h.header
+}
+
+// countingRule counts the requests served by the wrapped rule.
+type countingRule struct {
+	testx.ResponseRule
+	requests *atomic.Int32
+}
+
+func (c countingRule) Apply(resp *http.Response) {
+	c.requests.Add(1)
+	c.ResponseRule.Apply(resp)
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	indexRule, err := testx.NewSimpleUrlRule("https://charts.bitnami.com/bitnami/index.yaml", bitnamiIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c := helm.NewChecker(testx.MockHTTPClient(indexRule))
+	c.QualifierHosts = httpx.AllowHosts("charts.bitnami.com")
+
+	tests := []struct {
+		name       string
+		packageUrl string
+		wantErr    error
+	}{
+		{
+			name:       "Allowed host",
+			packageUrl: "pkg:helm/nginx@18.2.4?repository_url=https://charts.bitnami.com/bitnami",
+		},
+		{
+			name:       "Repository of another host",
+			packageUrl: "pkg:helm/nginx@18.2.4?repository_url=http://169.254.169.254/latest",
+			wantErr:    httpx.ErrHostNotAllowed,
+		},
+		{
+			name:       "OCI registry of another host",
+			packageUrl: "pkg:helm/podinfo@6.6.3?repository_url=oci://ghcr.io/stefanprodan/charts",
+			wantErr:    httpx.ErrHostNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
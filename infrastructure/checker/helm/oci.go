@@ -0,0 +1,273 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	helmConfigMediaType  = "application/vnd.cncf.helm.config.v1+json"
+
+	// tagPageSize is requested per page of the tags list - registries might return fewer tags
+	tagPageSize = 1000
+	// maxTagPages limits the pagination of the tags list
+	maxTagPages = 20
+)
+
+var (
+	ErrUnsupportedAuthentication = errors.New("unsupported registry authentication")
+	ErrTooManyTags               = errors.New("too many tags")
+)
+
+// latestFromRegistry looks up OCI charts e.g. oci://registry-1.docker.io/bitnamicharts - the appVersion is taken from
+// the chart config of the latest version.
+func (c Checker) latestFromRegistry(ctx context.Context, reference, chart string) (*ports.PackageInfo, error) {
+	host, namespace, _ := strings.Cut(strings.TrimSuffix(reference, "/"), "/")
+
+	registry := &ociRegistry{
+		client:     c.Client,
+		baseURL:    "https://" + host + "/v2/",
+		repository: path.Join(namespace, chart),
+	}
+
+	tags, err := registry.tags(ctx)
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return nil, fmt.Errorf("%w: %s in %s", ports.ErrNoMatchingPackageFound, chart, reference)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", registry.repository, err)
+	}
+
+	// helm replaces the + of build metadata with _ because + isn't allowed in tags
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		versions = append(versions, strings.ReplaceAll(tag, "_", "+"))
+	}
+
+	stable, preRelease := semverx.Latest(versions, semver.NewVersion)
+	if stable == "" {
+		return nil, fmt.Errorf("%w: no version of %s in %s", ports.ErrNoMatchingPackageFound, chart, reference)
+	}
+
+	latest, err := registry.chartMetadata(ctx, strings.ReplaceAll(stable, "+", "_"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart metadata of %s:%s: %w", registry.repository, stable, err)
+	}
+
+	return &ports.PackageInfo{
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		LatestAppVersion:        latest.AppVersion,
+		Deprecated:              latest.Deprecated,
+	}, nil
+}
+
+// ociRegistry is a minimal client of the OCI distribution API for a single repository.
+// Anonymous bearer tokens are requested when the registry challenges a request and reused for all further requests.
+type ociRegistry struct {
+	client     *http.Client
+	baseURL    string
+	repository string
+	token      string
+}
+
+func (r *ociRegistry) tags(ctx context.Context) ([]string, error) {
+	var (
+		tags    []string
+		nextURL = httpx.BaseURL(r.baseURL) + path.Join(r.repository, "tags", "list") + "?n=" + strconv.Itoa(tagPageSize)
+	)
+
+	for page := 0; nextURL != ""; page++ {
+		// reporting the latest of a truncated tags list would be misleading
+		if page == maxTagPages {
+			return nil, fmt.Errorf("%w: %s has more than %d pages of tags", ErrTooManyTags, r.repository, maxTagPages)
+		}
+
+		var (
+			result  ociTagList
+			pageURL = nextURL
+		)
+
+		nextURL = ""
+
+		err := r.fetch(ctx, pageURL, "application/json", func(resp *http.Response) error {
+			if next := httpx.NextLink(resp.Header.Get("Link")); next != "" {
+				resolved, err := url.Parse(pageURL)
+				if err == nil {
+					resolved, err = resolved.Parse(next)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				nextURL = resolved.String()
+			}
+
+			return json.NewDecoder(resp.Body).Decode(&result)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, result.Tags...)
+	}
+
+	return tags, nil
+}
+
+// chartMetadata reads the Chart.yaml metadata helm stores as config of the manifest.
+func (r *ociRegistry) chartMetadata(ctx context.Context, tag string) (metadata chartVersion, err error) {
+	var manifest ociManifest
+
+	manifestURL := httpx.BaseURL(r.baseURL) + path.Join(r.repository, "manifests", tag)
+	if err := r.fetch(ctx, manifestURL, ociManifestMediaType, requests.ToJSON(&manifest)); err != nil {
+		return chartVersion{}, err
+	}
+
+	if manifest.Config.MediaType != helmConfigMediaType {
+		return chartVersion{}, fmt.Errorf("%s:%s is not a helm chart but %s", r.repository, tag, manifest.Config.MediaType)
+	}
+
+	configURL := httpx.BaseURL(r.baseURL) + path.Join(r.repository, "blobs", manifest.Config.Digest)
+	if err := r.fetch(ctx, configURL, helmConfigMediaType, requests.ToJSON(&metadata)); err != nil {
+		return chartVersion{}, err
+	}
+
+	return metadata, nil
+}
+
+// fetch retries requests once with a bearer token if the registry challenges the anonymous request.
+func (r *ociRegistry) fetch(ctx context.Context, rawURL, accept string, handler requests.ResponseHandler) error {
+	var challenge string
+
+	build := func() *requests.Builder {
+		return requests.
+			URL(rawURL).
+			Client(r.client).
+			Accept(accept).
+			HeaderOptional("Authorization", bearer(r.token)).
+			AddValidator(func(resp *http.Response) error {
+				challenge = resp.Header.Get("WWW-Authenticate")
+				return nil
+			}).
+			AddValidator(requests.DefaultValidator).
+			Handle(handler)
+	}
+
+	err := build().Fetch(ctx)
+	if !requests.HasStatusErr(err, http.StatusUnauthorized) || r.token != "" {
+		return err
+	}
+
+	if err := r.authenticate(ctx, challenge); err != nil {
+		return err
+	}
+
+	return build().Fetch(ctx)
+}
+
+// authenticate requests an anonymous token for the realm, service and scope of a challenge
+// e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:bitnamicharts/nginx:pull".
+func (r *ociRegistry) authenticate(ctx context.Context, challenge string) error {
+	scheme, rawParams, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("%w: %q", ErrUnsupportedAuthentication, challenge)
+	}
+
+	params := parseChallengeParams(rawParams)
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return fmt.Errorf("%w: invalid realm %q", ErrUnsupportedAuthentication, params["realm"])
+	}
+
+	rb := requests.
+		URL(realm.String()).
+		Client(r.client)
+
+	for _, key := range []string{"service", "scope"} {
+		if value := params[key]; value != "" {
+			rb.Param(key, value)
+		}
+	}
+
+	var token ociToken
+	if err := rb.ToJSON(&token).Fetch(ctx); err != nil {
+		return fmt.Errorf("failed to request registry token: %w", err)
+	}
+
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+
+	if r.token == "" {
+		return fmt.Errorf("%w: empty token issued by %s", ErrUnsupportedAuthentication, realm.Host)
+	}
+
+	return nil
+}
+
+// parseChallengeParams parses the comma separated key="value" pairs of a WWW-Authenticate header
+// - values might contain commas themselves e.g. scope="repository:charts/nginx:pull,push".
+func parseChallengeParams(raw string) map[string]string {
+	params := make(map[string]string)
+
+	for raw = strings.TrimSpace(raw); raw != ""; {
+		key, rest, found := strings.Cut(raw, "=")
+		if !found {
+			break
+		}
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+		raw = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+
+	return params
+}
+
+func bearer(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	return "Bearer " + token
+}
+
+type ociTagList struct {
+	Tags []string `json:"tags"`
+}
+
+type ociManifest struct {
+	Config struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"config"`
+}
+
+type ociToken struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
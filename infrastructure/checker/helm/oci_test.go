@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func Test_parseChallengeParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]string
+	}{
+		{
+			name: "Docker Hub",
+			raw:  `realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:bitnamicharts/nginx:pull"`,
+			want: map[string]string{
+				"realm":   "https://auth.docker.io/token",
+				"service": "registry.docker.io",
+				"scope":   "repository:bitnamicharts/nginx:pull",
+			},
+		},
+		{
+			name: "Comma in quoted value",
+			raw:  `realm="https://ghcr.io/token", service="ghcr.io", scope="repository:charts/nginx:pull,push"`,
+			want: map[string]string{
+				"realm":   "https://ghcr.io/token",
+				"service": "ghcr.io",
+				"scope":   "repository:charts/nginx:pull,push",
+			},
+		},
+		{
+			name: "Unquoted values",
+			raw:  `Realm=https://registry.example.com/token,service=registry`,
+			want: map[string]string{
+				"realm":   "https://registry.example.com/token",
+				"service": "registry",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, parseChallengeParams(tt.raw))
+		})
+	}
+}
+
+func TestOCIRegistry_Tags_TooManyPages(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	registry := &ociRegistry{
+		client:     testx.MockHTTPClient(endlessTagsRule{requests: &requests}),
+		baseURL:    "https://registry.example.com/v2/",
+		repository: "charts/nginx",
+	}
+
+	_, err := registry.tags(testx.Context(t))
+	assert.ErrorIs(t, err, ErrTooManyTags)
+	assert.Equal(t, int32(maxTagPages), requests.Load())
+}
+
+// endlessTagsRule links every page of the tags list to another page.
+type endlessTagsRule struct {
+	requests *atomic.Int32
+}
+
+func (endlessTagsRule) Matches(req *http.Request) bool {
+	return req.URL.Path == "/v2/charts/nginx/tags/list" && req.URL.Query().Get("n") == "1000"
+}
+
+func (e endlessTagsRule) Apply(resp *http.Response) {
+	page := e.requests.Add(1)
+
+	resp.StatusCode = http.StatusOK
+	resp.Header = http.Header{"Link": {fmt.Sprintf(`</v2/charts/nginx/tags/list?last=%d&n=1000>; rel="next"`, page)}}
+	resp.Body = io.NopCloser(strings.NewReader(fmt.Sprintf(`{"name":"charts/nginx","tags":["1.0.%d"]}`, page)))
+}
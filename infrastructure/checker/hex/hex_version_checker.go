@@ -0,0 +1,150 @@
+package hex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/spdx"
+)
+
+const DefaultBaseURL = "https://hex.pm"
+
+var _ ports.ConditionalUpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL string) Checker {
+	return Checker{Client: client, BaseURL: baseURL}
+}
+
+// Checker queries the hex.pm API or a self-hosted repository implementing it.
+// The namespace of the package URL selects the organization repository a private package belongs to.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "hex"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := c.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (c Checker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
+	var hexResult hexPackage
+
+	requestPath := path.Join("api", "packages", packageUrl.Name)
+	if packageUrl.Namespace != "" {
+		requestPath = path.Join("api", "repos", packageUrl.Namespace, "packages", packageUrl.Name)
+	}
+
+	rb := requests.
+		URL(httpx.BaseURL(c.BaseURL)).
+		Path(requestPath).
+		Client(c.Client)
+
+	received, err := httpx.FetchConditional(ctx, rb, validators, requests.ToJSON(&hexResult))
+	if err != nil {
+		return nil, received, err
+	}
+
+	latestVersion := hexResult.latestVersion()
+	if latestVersion == "" {
+		return nil, received, fmt.Errorf("%w: no release of %s available", ports.ErrNoMatchingPackageFound, packageUrl.Name)
+	}
+
+	info := &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           hexResult.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latestVersion,
+		PackageManager: "hex",
+		LatestLicense:  spdx.Normalize(strings.Join(hexResult.Meta.Licenses, " OR ")),
+	}
+
+	if retirement, ok := hexResult.Retirements[packageUrl.Version]; ok {
+		info.Yanked = true
+		info.YankedReason = retirement.String()
+	}
+
+	return info, received, nil
+}
+
+type hexPackage struct {
+	Name     string `json:"name"`
+	Releases []struct {
+		Version string `json:"version"`
+	} `json:"releases"`
+	// Retirements are indexed by version
+	Retirements map[string]hexRetirement `json:"retirements"`
+	Meta        struct {
+		Licenses []string `json:"licenses"`
+	} `json:"meta"`
+}
+
+// latestVersion picks the greatest release that isn't retired - pre-releases only if there's no stable release.
+// Elixir requires all three version components hence all other versions are skipped.
+func (p hexPackage) latestVersion() string {
+	var stable, preReleases []*semver.Version
+
+	for _, r := range p.Releases {
+		if _, retired := p.Retirements[r.Version]; retired {
+			continue
+		}
+
+		parsed, err := semver.StrictNewVersion(r.Version)
+		if err != nil {
+			continue
+		}
+
+		if parsed.Prerelease() == "" {
+			stable = append(stable, parsed)
+		} else {
+			preReleases = append(preReleases, parsed)
+		}
+	}
+
+	candidates := stable
+	if len(candidates) == 0 {
+		candidates = preReleases
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	return slices.MaxFunc(candidates, func(a, b *semver.Version) int {
+		return a.Compare(b)
+	}).Original()
+}
+
+type hexRetirement struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (r hexRetirement) String() string {
+	if r.Message == "" {
+		return r.Reason
+	}
+
+	return r.Reason + ": " + r.Message
+}
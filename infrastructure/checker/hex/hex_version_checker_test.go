@@ -0,0 +1,121 @@
+package hex_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/hex"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/jason.json
+	jasonResponse []byte
+	//go:embed testdata/private_lib.json
+	privateLibResponse []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		baseURL      string
+		clientConfig map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr bool
+	}{
+		{
+			name: "Outdated existing dependency - pre-releases are skipped",
+			args: args{
+				packageUrl: "pkg:hex/jason@1.4.1",
+			},
+			fields: fields{
+				baseURL: hex.DefaultBaseURL,
+				clientConfig: map[string][]byte{
+					"https://hex.pm/api/packages/jason": jasonResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "jason",
+				CurrentVersion: "1.4.1",
+				LatestVersion:  "1.4.4",
+				PackageManager: "hex",
+				LatestLicense:  "Apache-2.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Retired version in organization repository on self-hosted instance",
+			args: args{
+				packageUrl: "pkg:hex/acme/private_lib@2.0.0",
+			},
+			fields: fields{
+				baseURL: "https://hex.example.com",
+				clientConfig: map[string][]byte{
+					"https://hex.example.com/api/repos/acme/packages/private_lib": privateLibResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "acme",
+				Name:           "private_lib",
+				CurrentVersion: "2.0.0",
+				LatestVersion:  "2.0.1",
+				PackageManager: "hex",
+				Yanked:         true,
+				YankedReason:   "invalid: Crashes on empty input, use 2.0.1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:hex/unknown@1.0.0",
+			},
+			fields: fields{
+				baseURL:      hex.DefaultBaseURL,
+				clientConfig: map[string][]byte{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			responseRules := make([]testx.ResponseRule, 0, len(tt.fields.clientConfig))
+			for rawUrl, resp := range tt.fields.clientConfig {
+				respRule, err := testx.NewSimpleUrlRule(rawUrl, resp)
+				if !assert.NoError(t, err) {
+					return
+				}
+				responseRules = append(responseRules, respRule)
+			}
+
+			c := hex.NewChecker(testx.MockHTTPClient(responseRules...), tt.fields.baseURL)
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Checker.LatestVersionFor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -14,9 +14,10 @@ import (
 	"github.com/package-url/packageurl-go"
 
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
 )
 
-var _ ports.UpdateChecker = (*Checker)(nil)
+var _ ports.ConditionalUpdateChecker = (*Checker)(nil)
 
 func NewChecker(client *http.Client) Checker {
 	return Checker{Client: client}
@@ -28,6 +29,16 @@ type Checker struct {
 
 // LatestVersionFor implements ports.UpdateChecker.
 func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := c.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (c Checker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
 	var metadataResult mavenMetadata
 
 	requestPath := path.Join(
@@ -37,19 +48,19 @@ func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.Pac
 		"maven-metadata.xml",
 	)
 
-	err := requests.
+	rb := requests.
 		URL("https://repo.maven.apache.org/").
 		Path(requestPath).
-		Client(c.Client).
-		ToDeserializer(xml.Unmarshal, &metadataResult).
-		Fetch(ctx)
+		Client(c.Client)
+
+	received, err := httpx.FetchConditional(ctx, rb, validators, requests.ToDeserializer(xml.Unmarshal, &metadataResult))
 	if err != nil {
-		return nil, err
+		return nil, received, err
 	}
 
 	latestVersion, err := metadataResult.latestVersion()
 	if err != nil {
-		return nil, err
+		return nil, received, err
 	}
 
 	return &ports.PackageInfo{
@@ -58,7 +69,7 @@ func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.Pac
 		CurrentVersion: packageUrl.Version,
 		LatestVersion:  latestVersion,
 		PackageManager: "maven",
-	}, nil
+	}, received, nil
 }
 
 // SupportedPackageType implements ports.UpdateChecker.
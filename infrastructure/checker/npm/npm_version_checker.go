@@ -2,6 +2,7 @@ package npm
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"path"
 	"strings"
@@ -10,9 +11,11 @@ import (
 	"github.com/package-url/packageurl-go"
 
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/spdx"
 )
 
-var _ ports.UpdateChecker = (*Checker)(nil)
+var _ ports.ConditionalUpdateChecker = (*Checker)(nil)
 
 func NewChecker(client *http.Client) Checker {
 	return Checker{Client: client}
@@ -29,16 +32,26 @@ func (Checker) SupportedPackageType() string {
 
 // LatestVersionFor implements ports.UpdateChecker.
 func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := c.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (c Checker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
 	var registryResult npmRegistryQueryResult
 
-	err := requests.
+	rb := requests.
 		URL("https://registry.npmjs.org").
 		Path(path.Join(packageUrl.Namespace, packageUrl.Name)).
-		Client(c.Client).
-		ToJSON(&registryResult).
-		Fetch(ctx)
+		Client(c.Client)
+
+	received, err := httpx.FetchConditional(ctx, rb, validators, requests.ToJSON(&registryResult))
 	if err != nil {
-		return nil, err
+		return nil, received, err
 	}
 
 	info := ports.PackageInfo{
@@ -53,7 +66,16 @@ func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.Pac
 		info.Name = registryResult.Name[idx:]
 	}
 
-	return &info, nil
+	info.CurrentLicense = registryResult.Versions[packageUrl.Version].License.String()
+	info.LatestLicense = registryResult.Versions[registryResult.DistTags.Latest].License.String()
+
+	// npm deprecates versions - a deprecated latest version deprecates the whole package
+	if message := registryResult.deprecationFor(packageUrl.Version); message != "" {
+		info.Deprecated = true
+		info.DeprecationMessage = message
+	}
+
+	return &info, received, nil
 }
 
 type npmRegistryQueryResult struct {
@@ -61,4 +83,44 @@ type npmRegistryQueryResult struct {
 	DistTags struct {
 		Latest string `json:"latest"`
 	} `json:"dist-tags"`
+	Versions map[string]npmVersionInfo `json:"versions"`
+}
+
+func (r npmRegistryQueryResult) deprecationFor(version string) string {
+	if v, ok := r.Versions[version]; ok && v.Deprecated != "" {
+		return v.Deprecated
+	}
+
+	return r.Versions[r.DistTags.Latest].Deprecated
+}
+
+type npmVersionInfo struct {
+	Deprecated string     `json:"deprecated"`
+	License    npmLicense `json:"license"`
+}
+
+// npmLicense is either an SPDX expression or - in legacy packages - an object with the license type.
+type npmLicense string
+
+func (l *npmLicense) UnmarshalJSON(data []byte) error {
+	var expression string
+	if err := json.Unmarshal(data, &expression); err == nil {
+		*l = npmLicense(expression)
+		return nil
+	}
+
+	var legacy struct {
+		Type string `json:"type"`
+	}
+
+	// unexpected license declarations must not fail the lookup
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*l = npmLicense(legacy.Type)
+	}
+
+	return nil
+}
+
+func (l npmLicense) String() string {
+	return spdx.Normalize(string(l))
 }
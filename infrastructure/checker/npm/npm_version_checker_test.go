@@ -17,6 +17,8 @@ var (
 	isEvenAIResponse []byte
 	//go:embed testdata/ampproject_remapping.json
 	ampProjectRemappingResponse []byte
+	//go:embed testdata/request.json
+	requestResponse []byte
 )
 
 func TestChecker_LatestVersionFor(t *testing.T) {
@@ -49,6 +51,8 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 				Name:           "is-even-ai",
 				CurrentVersion: "1.0.1",
 				LatestVersion:  "1.0.5",
+				CurrentLicense: "MIT",
+				LatestLicense:  "MIT",
 			},
 			wantErr: false,
 		},
@@ -70,6 +74,27 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Deprecated dependency",
+			args: args{
+				packageUrl: "pkg:npm/request@2.88.0",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://registry.npmjs.org/request": requestResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:               "request",
+				CurrentVersion:     "2.88.0",
+				LatestVersion:      "2.88.2",
+				Deprecated:         true,
+				DeprecationMessage: "request has been deprecated, see https://github.com/request/request/issues/3142",
+				CurrentLicense:     "Apache-2.0",
+				LatestLicense:      "Apache-2.0",
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -98,6 +123,10 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 
 			t.Log(got.Name)
 			assert.NotEmpty(t, got.LatestVersion)
+			assert.Equal(t, tt.want.Deprecated, got.Deprecated)
+			assert.Equal(t, tt.want.DeprecationMessage, got.DeprecationMessage)
+			assert.Equal(t, tt.want.CurrentLicense, got.CurrentLicense)
+			assert.Equal(t, tt.want.LatestLicense, got.LatestLicense)
 		})
 	}
 }
@@ -2,14 +2,19 @@ package nuget
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/carlmjohnson/requests"
 	"github.com/package-url/packageurl-go"
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/spdx"
 )
 
 var _ ports.UpdateChecker = (*Checker)(nil)
@@ -29,14 +34,7 @@ func (Checker) SupportedPackageType() string {
 
 // LatestVersionFor implements ports.UpdateChecker.
 func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
-	var nugetResult nugetQueryResult
-
-	err := requests.
-		URL("https://azuresearch-usnc.nuget.org/query").
-		Param("q", fmt.Sprintf("packageid:%s", packageUrl.Name)).
-		Client(c.Client).
-		ToJSON(&nugetResult).
-		Fetch(ctx)
+	nugetResult, err := c.search(ctx, packageUrl.Name, false)
 	if err != nil {
 		return nil, err
 	}
@@ -62,12 +60,92 @@ func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.Pac
 		return nil, fmt.Errorf("%w: %s", ports.ErrCurrentVersionGreaterThanLatest, nugetResult.Packages[0].Version)
 	}
 
-	return &ports.PackageInfo{
-		Name:           nugetResult.Packages[0].Id,
-		LatestVersion:  nugetResult.Packages[0].Version,
+	pkg := nugetResult.Packages[0]
+	info := &ports.PackageInfo{
+		Name:           pkg.Id,
+		LatestVersion:  pkg.Version,
 		CurrentVersion: packageUrl.Version,
 		PackageManager: "nuget",
-	}, nil
+	}
+
+	if info.Yanked, err = c.isUnlisted(ctx, pkg, packageUrl.Version); err != nil {
+		return nil, err
+	}
+
+	info.LatestLicense = c.licenseFor(ctx, pkg.Id, pkg.Version)
+	info.CurrentLicense = info.LatestLicense
+
+	if normalizeVersion(packageUrl.Version) != normalizeVersion(pkg.Version) {
+		info.CurrentLicense = c.licenseFor(ctx, pkg.Id, packageUrl.Version)
+	}
+
+	if pkg.Deprecation != nil {
+		info.Deprecated = true
+		info.DeprecationMessage = pkg.Deprecation.message()
+		if pkg.Deprecation.AlternatePackage != nil {
+			info.Replacement = pkg.Deprecation.AlternatePackage.Id
+		}
+	}
+
+	return info, nil
+}
+
+// search looks up the package by its ID - pre-releases are only listed if requested.
+func (c Checker) search(ctx context.Context, id string, prerelease bool) (nugetQueryResult, error) {
+	var result nugetQueryResult
+
+	err := requests.
+		URL("https://azuresearch-usnc.nuget.org/query").
+		Param("q", fmt.Sprintf("packageid:%s", id)).
+		Param("prerelease", strconv.FormatBool(prerelease)).
+		Param("semVerLevel", "2.0.0").
+		Client(c.Client).
+		ToJSON(&result).
+		Fetch(ctx)
+
+	return result, err
+}
+
+// isUnlisted checks whether the version is missing in the listed versions of the package
+// - pre-releases are only listed by searches including them.
+func (c Checker) isUnlisted(ctx context.Context, pkg nugetPackageInfo, version string) (bool, error) {
+	if pkg.isListed(version) {
+		return false, nil
+	}
+
+	result, err := c.search(ctx, pkg.Id, true)
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range result.Packages {
+		if strings.EqualFold(candidate.Id, pkg.Id) {
+			return !candidate.isListed(version), nil
+		}
+	}
+
+	return true, nil
+}
+
+// licenseFor reads the license of a specific version from its nuspec - licenses are best-effort hence errors are ignored.
+func (c Checker) licenseFor(ctx context.Context, id, version string) string {
+	var spec nuspec
+
+	lowerID := strings.ToLower(id)
+
+	err := requests.
+		URL("https://api.nuget.org/v3-flatcontainer/").
+		Path(path.Join(lowerID, normalizeVersion(version), lowerID+".nuspec")).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			return xml.NewDecoder(resp.Body).Decode(&spec)
+		}).
+		Fetch(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return spec.Metadata.license()
 }
 
 type nugetQueryResult struct {
@@ -76,9 +154,75 @@ type nugetQueryResult struct {
 }
 
 type nugetPackageInfo struct {
-	Id      string `json:"id"`
+	Id          string             `json:"id"`
+	Version     string             `json:"version"`
+	Title       string             `json:"title"`
+	Versions    []nugetVersionInfo `json:"versions"`
+	Deprecation *nugetDeprecation  `json:"deprecation"`
+}
+
+func (p nugetPackageInfo) isListed(version string) bool {
+	if len(p.Versions) == 0 {
+		return true
+	}
+
+	normalized := normalizeVersion(version)
+	for _, v := range p.Versions {
+		if normalizeVersion(v.Version) == normalized {
+			return true
+		}
+	}
+
+	return false
+}
+
+type nugetVersionInfo struct {
 	Version string `json:"version"`
-	Title   string `json:"title"`
+}
+
+type nugetDeprecation struct {
+	Message          string   `json:"message"`
+	Reasons          []string `json:"reasons"`
+	AlternatePackage *struct {
+		Id string `json:"id"`
+	} `json:"alternatePackage"`
+}
+
+func (d nugetDeprecation) message() string {
+	if d.Message != "" {
+		return d.Message
+	}
+
+	return strings.Join(d.Reasons, ", ")
+}
+
+type nuspec struct {
+	Metadata nuspecMetadata `xml:"metadata"`
+}
+
+type nuspecMetadata struct {
+	License struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"license"`
+	LicenseURL string `xml:"licenseUrl"`
+}
+
+// licenseURLPrefix is used for the deprecated licenseUrl of packages declaring a license expression.
+const licenseURLPrefix = "https://licenses.nuget.org/"
+
+func (m nuspecMetadata) license() string {
+	if m.License.Type == "expression" {
+		return spdx.Normalize(m.License.Value)
+	}
+
+	if expression, ok := strings.CutPrefix(m.LicenseURL, licenseURLPrefix); ok {
+		if unescaped, err := url.PathUnescape(expression); err == nil {
+			return spdx.Normalize(unescaped)
+		}
+	}
+
+	return ""
 }
 
 func parseNugetVersion(version string) (*semver.Version, error) {
@@ -88,3 +232,32 @@ func parseNugetVersion(version string) (*semver.Version, error) {
 
 	return semver.NewVersion(version)
 }
+
+// normalizeVersion applies the normalization of NuGet e.g. 1.0 and 1.00.0.0 both become 1.0.0
+// - build metadata is dropped and pre-release labels are compared case-insensitive.
+func normalizeVersion(version string) string {
+	version, _, _ = strings.Cut(version, "+")
+	release, preRelease, isPreRelease := strings.Cut(version, "-")
+
+	parts := strings.Split(release, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+
+	for i, part := range parts {
+		if n, err := strconv.Atoi(part); err == nil {
+			parts[i] = strconv.Itoa(n)
+		}
+	}
+
+	if len(parts) == 4 && parts[3] == "0" {
+		parts = parts[:3]
+	}
+
+	normalized := strings.Join(parts, ".")
+	if isPreRelease {
+		normalized += "-" + preRelease
+	}
+
+	return strings.ToLower(normalized)
+}
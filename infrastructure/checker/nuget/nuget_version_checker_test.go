@@ -12,8 +12,18 @@ import (
 	"github.com/prskr/aucs/internal/testx"
 )
 
-//go:embed testdata/BouncyCastle.Cryptography.json
-var bouncyCastleCryptographyResponse []byte
+var (
+	//go:embed testdata/BouncyCastle.Cryptography.json
+	bouncyCastleCryptographyResponse []byte
+	//go:embed testdata/BouncyCastle.Cryptography-prerelease.json
+	bouncyCastleCryptographyPreReleaseResponse []byte
+	//go:embed testdata/WindowsAzure.Storage.json
+	windowsAzureStorageResponse []byte
+	//go:embed testdata/bouncycastle.cryptography.2.2.1.nuspec
+	bouncyCastleCryptography221Nuspec []byte
+	//go:embed testdata/bouncycastle.cryptography.2.4.0.nuspec
+	bouncyCastleCryptography240Nuspec []byte
+)
 
 func TestChecker_LatestVersionFor(t *testing.T) {
 	t.Parallel()
@@ -38,7 +48,9 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 			},
 			fields: fields{
 				clientConfig: map[string][]byte{
-					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography": bouncyCastleCryptographyResponse,
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=false&semVerLevel=2.0.0": bouncyCastleCryptographyResponse,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.2.1/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography221Nuspec,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.4.0/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography240Nuspec,
 				},
 			},
 			want: &ports.PackageInfo{
@@ -46,6 +58,113 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 				CurrentVersion: "2.2.1",
 				LatestVersion:  "2.4.0",
 				PackageManager: "nuget",
+				CurrentLicense: "MIT",
+				LatestLicense:  "MIT",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unlisted version",
+			args: args{
+				packageUrl: "pkg:nuget/BouncyCastle.Cryptography@2.2.2",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=false&semVerLevel=2.0.0": bouncyCastleCryptographyResponse,
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=true&semVerLevel=2.0.0":  bouncyCastleCryptographyPreReleaseResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "BouncyCastle.Cryptography",
+				CurrentVersion: "2.2.2",
+				LatestVersion:  "2.4.0",
+				PackageManager: "nuget",
+				Yanked:         true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Listed pre-release",
+			args: args{
+				packageUrl: "pkg:nuget/BouncyCastle.Cryptography@2.4.0-beta1",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=false&semVerLevel=2.0.0": bouncyCastleCryptographyResponse,
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=true&semVerLevel=2.0.0":  bouncyCastleCryptographyPreReleaseResponse,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.4.0/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography240Nuspec,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "BouncyCastle.Cryptography",
+				CurrentVersion: "2.4.0-beta1",
+				LatestVersion:  "2.4.0",
+				PackageManager: "nuget",
+				LatestLicense:  "MIT",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Non-normalized version",
+			args: args{
+				packageUrl: "pkg:nuget/BouncyCastle.Cryptography@2.2.1.0",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=false&semVerLevel=2.0.0": bouncyCastleCryptographyResponse,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.2.1/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography221Nuspec,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.4.0/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography240Nuspec,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "BouncyCastle.Cryptography",
+				CurrentVersion: "2.2.1.0",
+				LatestVersion:  "2.4.0",
+				PackageManager: "nuget",
+				CurrentLicense: "MIT",
+				LatestLicense:  "MIT",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Version with omitted patch",
+			args: args{
+				packageUrl: "pkg:nuget/BouncyCastle.Cryptography@2.4",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:BouncyCastle.Cryptography&prerelease=false&semVerLevel=2.0.0": bouncyCastleCryptographyResponse,
+					"https://api.nuget.org/v3-flatcontainer/bouncycastle.cryptography/2.4.0/bouncycastle.cryptography.nuspec":           bouncyCastleCryptography240Nuspec,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "BouncyCastle.Cryptography",
+				CurrentVersion: "2.4",
+				LatestVersion:  "2.4.0",
+				PackageManager: "nuget",
+				CurrentLicense: "MIT",
+				LatestLicense:  "MIT",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Deprecated package with alternative",
+			args: args{
+				packageUrl: "pkg:nuget/WindowsAzure.Storage@9.3.2",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://azuresearch-usnc.nuget.org/query?q=packageid:WindowsAzure.Storage&prerelease=false&semVerLevel=2.0.0": windowsAzureStorageResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:               "WindowsAzure.Storage",
+				CurrentVersion:     "9.3.2",
+				LatestVersion:      "9.3.3",
+				PackageManager:     "nuget",
+				Deprecated:         true,
+				DeprecationMessage: "Please use the Azure.Storage.Blobs, Azure.Storage.Queues and Azure.Storage.Files.Shares packages instead.",
+				Replacement:        "Azure.Storage.Blobs",
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,128 @@
+package pub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+const (
+	DefaultBaseURL = "https://pub.dev"
+
+	// repositoryURLQualifier overrides the hosted URL for a single package
+	repositoryURLQualifier = "repository_url"
+	acceptPubV2            = "application/vnd.pub.v2+json"
+)
+
+var _ ports.ConditionalUpdateChecker = (*Checker)(nil)
+
+func NewChecker(client *http.Client, baseURL string) Checker {
+	return Checker{Client: client, BaseURL: baseURL}
+}
+
+// Checker implements the hosted pub repository specification v2 e.g. pub.dev or the host configured via PUB_HOSTED_URL.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+	// QualifierHosts restricts the hosts the repository_url qualifier may select
+	QualifierHosts httpx.AllowedHosts
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "pub"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := c.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (c Checker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
+	var pubResult pubPackage
+
+	baseURL := c.BaseURL
+	if repositoryURL, ok := packageUrl.Qualifiers.Map()[repositoryURLQualifier]; ok && repositoryURL != "" {
+		if err := c.QualifierHosts.Check(repositoryURL); err != nil {
+			return nil, ports.CacheValidators{}, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+		}
+
+		baseURL = repositoryURL
+	}
+
+	rb := requests.
+		URL(httpx.BaseURL(baseURL)).
+		Path(path.Join("api", "packages", packageUrl.Name)).
+		Accept(acceptPubV2).
+		Client(c.Client)
+
+	received, err := httpx.FetchConditional(ctx, rb, validators, requests.ToJSON(&pubResult))
+	if err != nil {
+		return nil, received, err
+	}
+
+	stable, preRelease := pubResult.latestVersions()
+	if stable == "" {
+		return nil, received, fmt.Errorf("%w: no version of %s available", ports.ErrNoMatchingPackageFound, packageUrl.Name)
+	}
+
+	info := &ports.PackageInfo{
+		Name:                    pubResult.Name,
+		CurrentVersion:          packageUrl.Version,
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		PackageManager:          "pub",
+		Deprecated:              pubResult.IsDiscontinued,
+		Replacement:             pubResult.ReplacedBy,
+		Yanked:                  pubResult.isRetracted(packageUrl.Version),
+	}
+
+	return info, received, nil
+}
+
+type pubPackage struct {
+	Name           string       `json:"name"`
+	IsDiscontinued bool         `json:"isDiscontinued"`
+	ReplacedBy     string       `json:"replacedBy"`
+	Versions       []pubVersion `json:"versions"`
+}
+
+type pubVersion struct {
+	Version   string `json:"version"`
+	Retracted bool   `json:"retracted"`
+}
+
+// latestVersions determines the latest versions of the package - retracted versions are excluded.
+func (p pubPackage) latestVersions() (stable, preRelease string) {
+	versions := make([]string, 0, len(p.Versions))
+
+	for _, v := range p.Versions {
+		if !v.Retracted {
+			versions = append(versions, v.Version)
+		}
+	}
+
+	return semverx.Latest(versions, semver.NewVersion)
+}
+
+func (p pubPackage) isRetracted(version string) bool {
+	return slices.ContainsFunc(p.Versions, func(v pubVersion) bool {
+		return v.Retracted && v.Version == version
+	})
+}
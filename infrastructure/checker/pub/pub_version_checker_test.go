@@ -0,0 +1,157 @@
+package pub_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/pub"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/http.json
+	httpResponse []byte
+	//go:embed testdata/flutter_markdown.json
+	flutterMarkdownResponse []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		baseURL      string
+		clientConfig map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr bool
+	}{
+		{
+			name: "Retracted version with newer pre-release",
+			args: args{
+				packageUrl: "pkg:pub/http@1.2.1",
+			},
+			fields: fields{
+				baseURL: pub.DefaultBaseURL,
+				clientConfig: map[string][]byte{
+					"https://pub.dev/api/packages/http": httpResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:                    "http",
+				CurrentVersion:          "1.2.1",
+				LatestVersion:           "1.2.2",
+				LatestPreReleaseVersion: "1.3.0-wip",
+				PackageManager:          "pub",
+				Yanked:                  true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Discontinued package on custom host below path prefix",
+			args: args{
+				packageUrl: "pkg:pub/flutter_markdown@0.7.6",
+			},
+			fields: fields{
+				baseURL: "https://pub.example.com/pub",
+				clientConfig: map[string][]byte{
+					"https://pub.example.com/pub/api/packages/flutter_markdown": flutterMarkdownResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "flutter_markdown",
+				CurrentVersion: "0.7.6",
+				LatestVersion:  "0.7.7",
+				PackageManager: "pub",
+				Deprecated:     true,
+				Replacement:    "flutter_markdown_plus",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Repository URL qualifier overrides host",
+			args: args{
+				packageUrl: "pkg:pub/flutter_markdown@0.7.7?repository_url=https://pub.example.com/pub/",
+			},
+			fields: fields{
+				baseURL: pub.DefaultBaseURL,
+				clientConfig: map[string][]byte{
+					"https://pub.example.com/pub/api/packages/flutter_markdown": flutterMarkdownResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "flutter_markdown",
+				CurrentVersion: "0.7.7",
+				LatestVersion:  "0.7.7",
+				PackageManager: "pub",
+				Deprecated:     true,
+				Replacement:    "flutter_markdown_plus",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			responseRules := make([]testx.ResponseRule, 0, len(tt.fields.clientConfig))
+			for rawUrl, resp := range tt.fields.clientConfig {
+				respRule, err := testx.NewSimpleUrlRule(rawUrl, resp)
+				if !assert.NoError(t, err) {
+					return
+				}
+				responseRules = append(responseRules, respRule)
+			}
+
+			c := pub.NewChecker(testx.MockHTTPClient(responseRules...), tt.fields.baseURL)
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Checker.LatestVersionFor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	c := pub.NewChecker(testx.MockHTTPClient(), pub.DefaultBaseURL)
+	c.QualifierHosts = httpx.AllowHosts("example.com")
+
+	for _, packageUrl := range []string{
+		"pkg:pub/http@1.2.1?repository_url=http://169.254.169.254/latest",
+		"pkg:pub/http@1.2.1?repository_url=file:///etc/passwd",
+	} {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+			assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+		})
+	}
+}
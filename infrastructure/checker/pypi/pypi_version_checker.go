@@ -4,14 +4,18 @@ import (
 	"context"
 	"net/http"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/carlmjohnson/requests"
 	"github.com/package-url/packageurl-go"
 
 	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/spdx"
 )
 
-var _ ports.UpdateChecker = (*Checker)(nil)
+var _ ports.ConditionalUpdateChecker = (*Checker)(nil)
 
 func NewChecker(client *http.Client) Checker {
 	return Checker{Client: client}
@@ -23,24 +27,62 @@ type Checker struct {
 
 // LatestVersionFor implements ports.UpdateChecker.
 func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := c.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (c Checker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
 	var pypiResult pypiQueryResult
 
-	err := requests.
+	rb := requests.
 		URL("https://pypi.org").
 		Path(path.Join("pypi", packageUrl.Name, "json")).
-		Client(c.Client).
-		ToJSON(&pypiResult).
-		Fetch(ctx)
+		Client(c.Client)
+
+	received, err := httpx.FetchConditional(ctx, rb, validators, requests.ToJSON(&pypiResult))
 	if err != nil {
-		return nil, err
+		return nil, received, err
 	}
 
-	return &ports.PackageInfo{
+	info := &ports.PackageInfo{
 		Name:           pypiResult.Info.Name,
 		CurrentVersion: packageUrl.Version,
 		LatestVersion:  pypiResult.Info.Version,
 		PackageManager: "pypi",
-	}, nil
+		Deprecated:     slices.Contains(pypiResult.Info.Classifiers, inactiveClassifier),
+	}
+
+	info.Yanked, info.YankedReason = pypiResult.yankedStatusFor(packageUrl.Version)
+	info.LatestLicense = pypiResult.Info.license()
+	info.CurrentLicense = info.LatestLicense
+
+	if packageUrl.Version != "" && packageUrl.Version != pypiResult.Info.Version {
+		info.CurrentLicense = c.licenseFor(ctx, packageUrl.Name, packageUrl.Version)
+	}
+
+	return info, received, nil
+}
+
+// licenseFor fetches the metadata of a specific version - licenses are best-effort hence errors are ignored.
+func (c Checker) licenseFor(ctx context.Context, name, version string) string {
+	var versionResult pypiQueryResult
+
+	err := requests.
+		URL("https://pypi.org").
+		Path(path.Join("pypi", name, version, "json")).
+		Client(c.Client).
+		ToJSON(&versionResult).
+		Fetch(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return versionResult.Info.license()
 }
 
 // SupportedPackageType implements ports.UpdateChecker.
@@ -48,11 +90,58 @@ func (Checker) SupportedPackageType() string {
 	return "pypi"
 }
 
+// inactiveClassifier is the closest PyPI has to deprecating a whole project.
+const inactiveClassifier = "Development Status :: 7 - Inactive"
+
 type pypiQueryResult struct {
-	Info pypiPackageInfo `json:"info"`
+	Info     pypiPackageInfo              `json:"info"`
+	Releases map[string][]pypiReleaseFile `json:"releases"`
+}
+
+// yankedStatusFor reports a version as yanked if all of its files were yanked.
+func (r pypiQueryResult) yankedStatusFor(version string) (yanked bool, reason string) {
+	files := r.Releases[version]
+	if len(files) == 0 {
+		return false, ""
+	}
+
+	for _, f := range files {
+		if !f.Yanked {
+			return false, ""
+		}
+
+		if reason == "" {
+			reason = f.YankedReason
+		}
+	}
+
+	return true, reason
 }
 
 type pypiPackageInfo struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name              string   `json:"name"`
+	Version           string   `json:"version"`
+	Classifiers       []string `json:"classifiers"`
+	License           string   `json:"license"`
+	LicenseExpression string   `json:"license_expression"`
+}
+
+// maxLicenseNameLength distinguishes license names from full license texts some projects put in the license field.
+const maxLicenseNameLength = 64
+
+// license prefers the SPDX expression of PEP 639 over the free-form license field and the trove classifiers.
+func (i pypiPackageInfo) license() string {
+	switch {
+	case i.LicenseExpression != "":
+		return spdx.Normalize(i.LicenseExpression)
+	case i.License != "" && len(i.License) <= maxLicenseNameLength && !strings.Contains(i.License, "\n"):
+		return spdx.Normalize(i.License)
+	default:
+		return spdx.FromClassifiers(i.Classifiers)
+	}
+}
+
+type pypiReleaseFile struct {
+	Yanked       bool   `json:"yanked"`
+	YankedReason string `json:"yanked_reason"`
 }
@@ -12,8 +12,14 @@ import (
 	"github.com/prskr/aucs/internal/testx"
 )
 
-//go:embed testdata/requests.json
-var requestsResponse []byte
+var (
+	//go:embed testdata/requests.json
+	requestsResponse []byte
+	//go:embed testdata/requests-2.29.0.json
+	requests2290Response []byte
+	//go:embed testdata/yanked.json
+	yankedResponse []byte
+)
 
 func TestChecker_LatestVersionFor(t *testing.T) {
 	t.Parallel()
@@ -38,7 +44,8 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 			},
 			fields: fields{
 				clientConfig: map[string][]byte{
-					"https://pypi.org/pypi/requests/json": requestsResponse,
+					"https://pypi.org/pypi/requests/json":        requestsResponse,
+					"https://pypi.org/pypi/requests/2.29.0/json": requests2290Response,
 				},
 			},
 			want: &ports.PackageInfo{
@@ -46,6 +53,29 @@ func TestChecker_LatestVersionFor(t *testing.T) {
 				CurrentVersion: "2.29.0",
 				LatestVersion:  "2.32.3",
 				PackageManager: "pypi",
+				CurrentLicense: "Apache-2.0",
+				LatestLicense:  "Apache-2.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Yanked version of inactive project",
+			args: args{
+				packageUrl: "pkg:pypi/yanked-example@1.0.0",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://pypi.org/pypi/yanked-example/json": yankedResponse,
+				},
+			},
+			want: &ports.PackageInfo{
+				Name:           "yanked-example",
+				CurrentVersion: "1.0.0",
+				LatestVersion:  "1.1.0",
+				PackageManager: "pypi",
+				Deprecated:     true,
+				Yanked:         true,
+				YankedReason:   "Broken metadata",
 			},
 			wantErr: false,
 		},
@@ -1,26 +1,66 @@
 package checker
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/carlmjohnson/requests"
 	"github.com/package-url/packageurl-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/prskr/aucs/core/ports"
 )
 
+var tracer = otel.Tracer("github.com/prskr/aucs/infrastructure/checker")
+
+// CachePolicy controls how long lookup results are considered fresh.
+type CachePolicy struct {
+	// TTL applies to successful lookups.
+	TTL time.Duration
+	// NegativeTTL applies to lookups that did not find a package or found ambiguous packages.
+	NegativeTTL time.Duration
+	// StaleWhileRevalidate is the grace window after expiry in which stale results are served
+	// while they're refreshed in the background.
+	StaleWhileRevalidate time.Duration
+	// RefreshTimeout limits the duration of background refreshes.
+	RefreshTimeout time.Duration
+}
+
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		TTL:                  6 * time.Hour,
+		NegativeTTL:          1 * time.Hour,
+		StaleWhileRevalidate: 1 * time.Hour,
+		RefreshTimeout:       30 * time.Second,
+	}
+}
+
 func NewRegistry(kv ports.KeyValueStore) *Registry {
 	return &Registry{
 		KV:             kv,
+		Policy:         DefaultCachePolicy(),
+		Observer:       nopObserver{},
 		CheckersByType: make(map[string]ports.UpdateChecker),
 	}
 }
 
 type Registry struct {
 	KV             ports.KeyValueStore
+	Policy         CachePolicy
+	Observer       ports.LookupObserver
 	CheckersByType map[string]ports.UpdateChecker
+
+	refreshing sync.Map
+	refreshWg  sync.WaitGroup
 }
 
 func (r *Registry) Register(checkers ...ports.UpdateChecker) {
@@ -29,26 +69,35 @@ func (r *Registry) Register(checkers ...ports.UpdateChecker) {
 	}
 }
 
-func (r Registry) LatestVersionFor(ctx context.Context, packageUrl string) (*ports.PackageInfo, error) {
-	purl, err := packageurl.FromString(packageUrl)
-	if err != nil {
-		return nil, err
-	}
+func (r *Registry) LatestVersionFor(ctx context.Context, packageUrl string) (info *ports.PackageInfo, err error) {
+	ctx, span := tracer.Start(ctx, "Registry.LatestVersionFor", trace.WithAttributes(
+		attribute.String("aucs.package_url", packageUrl),
+	))
+	defer span.End()
 
 	var (
-		cacheKey = cacheKeyFor(purl)
-		info     = new(ports.PackageInfo)
+		purl        packageurl.PackageURL
+		cacheStatus = ports.CacheStatusMiss
 	)
 
-	rawInfo, err := r.KV.Get(ctx, cacheKey)
-	if err != nil {
-		if !errors.Is(err, ports.ErrNoKVEntryForKey) {
-			return nil, err
+	// invalid package URLs are observed as well - with an empty ecosystem
+	defer func() {
+		r.Observer.ObserveLookup(purl.Type, cacheStatus, err)
+
+		span.SetAttributes(
+			attribute.String("aucs.ecosystem", purl.Type),
+			attribute.String("aucs.cache.status", string(cacheStatus)),
+			attribute.String("aucs.lookup.outcome", string(ports.LookupOutcomeFor(err))),
+		)
+
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
 		}
-	}
+	}()
 
-	if rawInfo != nil {
-		return info, json.Unmarshal(rawInfo, info)
+	purl, err = packageurl.FromString(packageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ports.ErrInvalidPackageURL, err)
 	}
 
 	// Get the checker for the package type
@@ -57,20 +106,239 @@ func (r Registry) LatestVersionFor(ctx context.Context, packageUrl string) (*por
 		return nil, fmt.Errorf("%w: %s", ports.ErrNoCheckerForPackageType, purl.Type)
 	}
 
-	// Delegate the call to the checker
-	info, err = checker.LatestVersionFor(ctx, purl)
+	cacheKey := CacheKeyFor(purl)
+
+	cached, err := r.cachedEntry(ctx, cacheKey)
 	if err != nil {
 		return nil, err
 	}
 
-	rawInfo, err = json.Marshal(info)
+	if cached != nil {
+		switch age := time.Since(cached.FetchedAt); {
+		case age < r.Policy.ttlFor(cached):
+			cacheStatus = ports.CacheStatusHit
+			return cached.result(purl, cacheStatus)
+		case age < r.Policy.ttlFor(cached)+r.Policy.StaleWhileRevalidate:
+			cacheStatus = ports.CacheStatusStale
+			r.refreshInBackground(ctx, checker, purl, cacheKey, cached)
+			return cached.result(purl, cacheStatus)
+		}
+	}
+
+	entry, err := r.fetch(ctx, checker, purl, cacheKey, cached)
 	if err != nil {
 		return nil, err
 	}
 
-	return info, r.KV.Put(ctx, cacheKey, rawInfo)
+	return entry.result(purl, cacheStatus)
 }
 
-func cacheKeyFor(purl packageurl.PackageURL) []byte {
-	return bytes.Join([][]byte{[]byte(purl.Type), []byte(purl.Namespace), []byte(purl.Name)}, []byte("/"))
+// Wait blocks until all background refreshes are done.
+// It has to be called before the underlying KV store is closed.
+func (r *Registry) Wait() {
+	r.refreshWg.Wait()
 }
+
+func (r *Registry) refreshInBackground(
+	ctx context.Context,
+	checker ports.UpdateChecker,
+	purl packageurl.PackageURL,
+	cacheKey []byte,
+	stale *cacheEntry,
+) {
+	if _, loaded := r.refreshing.LoadOrStore(string(cacheKey), struct{}{}); loaded {
+		return
+	}
+
+	r.refreshWg.Add(1)
+
+	go func() {
+		defer func() {
+			r.refreshing.Delete(string(cacheKey))
+			r.refreshWg.Done()
+		}()
+
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.Policy.RefreshTimeout)
+		defer cancel()
+
+		// the lookup span usually ends before the refresh - start a new trace linked to it
+		refreshCtx, span := tracer.Start(refreshCtx, "Registry.Refresh",
+			trace.WithNewRoot(),
+			trace.WithLinks(trace.LinkFromContext(ctx)),
+			trace.WithAttributes(attribute.String("aucs.package_url", purl.ToString())),
+		)
+		defer span.End()
+
+		if _, err := r.fetch(refreshCtx, checker, purl, cacheKey, stale); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			slog.WarnContext(ctx, "Failed to refresh stale cache entry",
+				slog.String("package_url", purl.ToString()),
+				slog.String("err", err.Error()),
+			)
+		}
+	}()
+}
+
+func (r *Registry) fetch(
+	ctx context.Context,
+	checker ports.UpdateChecker,
+	purl packageurl.PackageURL,
+	cacheKey []byte,
+	previous *cacheEntry,
+) (*cacheEntry, error) {
+	var (
+		entry = &cacheEntry{FetchedAt: time.Now().UTC()}
+		err   error
+	)
+
+	// Delegate the call to the checker - revalidate the previous result if possible
+	if conditional, ok := checker.(ports.ConditionalUpdateChecker); ok {
+		var validators ports.CacheValidators
+		if previous.canRevalidate() {
+			validators = previous.Validators
+		}
+
+		entry.Info, entry.Validators, err = conditional.LatestVersionIfModified(ctx, purl, validators)
+		if errors.Is(err, ports.ErrNotModified) && previous.canRevalidate() {
+			entry.Info, err = previous.Info, nil
+			if entry.Validators.IsZero() {
+				entry.Validators = previous.Validators
+			}
+		}
+	} else {
+		entry.Info, err = checker.LatestVersionFor(ctx, purl)
+	}
+
+	if err != nil {
+		if requests.HasStatusErr(err, http.StatusNotFound) {
+			err = fmt.Errorf("%w: %w", ports.ErrNoMatchingPackageFound, err)
+		}
+
+		var ok bool
+		if entry.NegativeResult, ok = negativeResultFor(err); !ok {
+			return nil, err
+		}
+
+		entry.Info = nil
+		entry.Error = err.Error()
+	}
+
+	rawEntry, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.KV.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       cacheKey,
+		Value:     rawEntry,
+		StoredAt:  entry.FetchedAt,
+		ExpiresAt: entry.FetchedAt.Add(r.Policy.ttlFor(entry) + r.Policy.StaleWhileRevalidate),
+	})
+
+	return entry, err
+}
+
+func (r *Registry) cachedEntry(ctx context.Context, cacheKey []byte) (*cacheEntry, error) {
+	rawEntry, err := r.KV.Get(ctx, cacheKey)
+	if err != nil {
+		if errors.Is(err, ports.ErrNoKVEntryForKey) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(rawEntry, entry); err != nil {
+		return nil, err
+	}
+
+	// entries written by previous versions only contain the package info
+	// they're still within their TTL otherwise the KV store wouldn't have returned them
+	if entry.FetchedAt.IsZero() {
+		info := new(ports.PackageInfo)
+		if err := json.Unmarshal(rawEntry, info); err != nil {
+			return nil, err
+		}
+
+		return &cacheEntry{Info: info, FetchedAt: time.Now()}, nil
+	}
+
+	// neither positive nor negative result - treat like a cache miss
+	if entry.Info == nil && entry.NegativeResult == "" {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+const (
+	negativeResultNotFound  = "not_found"
+	negativeResultAmbiguous = "ambiguous"
+)
+
+// cacheEntry is the cached result of a single lookup.
+// It either contains the package info or a negative result.
+type cacheEntry struct {
+	Info           *ports.PackageInfo    `json:"info,omitempty"`
+	NegativeResult string                `json:"negative_result,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	FetchedAt      time.Time             `json:"fetched_at"`
+	Validators     ports.CacheValidators `json:"validators"`
+}
+
+func (e *cacheEntry) canRevalidate() bool {
+	return e != nil && e.Info != nil && !e.Validators.IsZero()
+}
+
+func (e *cacheEntry) result(purl packageurl.PackageURL, cacheStatus ports.CacheStatus) (*ports.PackageInfo, error) {
+	switch e.NegativeResult {
+	case negativeResultNotFound:
+		return nil, cachedLookupError{kind: ports.ErrNoMatchingPackageFound, msg: e.Error}
+	case negativeResultAmbiguous:
+		return nil, cachedLookupError{kind: ports.ErrAmbiguousPackageFound, msg: e.Error}
+	}
+
+	info := *e.Info
+	info.CurrentVersion = purl.Version
+	info.CacheStatus = cacheStatus
+
+	return &info, nil
+}
+
+func (p CachePolicy) ttlFor(e *cacheEntry) time.Duration {
+	if e.NegativeResult != "" {
+		return p.NegativeTTL
+	}
+
+	return p.TTL
+}
+
+func negativeResultFor(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ports.ErrNoMatchingPackageFound):
+		return negativeResultNotFound, true
+	case errors.Is(err, ports.ErrAmbiguousPackageFound):
+		return negativeResultAmbiguous, true
+	default:
+		return "", false
+	}
+}
+
+// cachedLookupError restores a cached negative result with its original message.
+type cachedLookupError struct {
+	kind error
+	msg  string
+}
+
+func (e cachedLookupError) Error() string {
+	return e.msg
+}
+
+func (e cachedLookupError) Unwrap() error {
+	return e.kind
+}
+
+type nopObserver struct{}
+
+func (nopObserver) ObserveLookup(string, ports.CacheStatus, error) {}
@@ -0,0 +1,227 @@
+package checker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestRegistry_LatestVersionFor_NegativeResultsAreCached(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeChecker{err: fmt.Errorf("%w: is-even-ai", ports.ErrNoMatchingPackageFound)}
+	registry := checker.NewRegistry(db.NewMemoryKVStore(time.Hour))
+	registry.Register(fake)
+
+	for range 2 {
+		_, err := registry.LatestVersionFor(testx.Context(t), "pkg:npm/is-even-ai@1.0.1")
+		assert.ErrorIs(t, err, ports.ErrNoMatchingPackageFound)
+	}
+
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
+
+func TestRegistry_LatestVersionFor_InvalidPackageURLsAreObserved(t *testing.T) {
+	t.Parallel()
+
+	observer := new(recordingObserver)
+	registry := checker.NewRegistry(db.NewMemoryKVStore(time.Hour))
+	registry.Observer = observer
+
+	_, err := registry.LatestVersionFor(testx.Context(t), "npm/is-even-ai@1.0.1")
+	assert.ErrorIs(t, err, ports.ErrInvalidPackageURL)
+
+	if assert.Len(t, observer.lookups, 1) {
+		assert.Empty(t, observer.lookups[0].packageType)
+		assert.Equal(t, ports.LookupOutcomeInvalid, ports.LookupOutcomeFor(observer.lookups[0].err))
+	}
+}
+
+func TestRegistry_LatestVersionFor_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	kv := db.NewMemoryKVStore(time.Hour)
+	fake := &fakeChecker{
+		info:       &ports.PackageInfo{Name: "is-even-ai", LatestVersion: "1.0.5"},
+		validators: ports.CacheValidators{ETag: `"v2"`},
+	}
+
+	registry := checker.NewRegistry(kv)
+	registry.Policy.TTL = time.Hour
+	registry.Policy.StaleWhileRevalidate = time.Hour
+	registry.Register(fake)
+
+	stale, _ := json.Marshal(map[string]any{
+		"info":       ports.PackageInfo{Name: "is-even-ai", LatestVersion: "1.0.4"},
+		"fetched_at": time.Now().Add(-90 * time.Minute),
+		"validators": ports.CacheValidators{ETag: `"v1"`},
+	})
+	_ = kv.Put(ctx, []byte("npm//is-even-ai@1.0.1"), stale)
+
+	got, err := registry.LatestVersionFor(ctx, "pkg:npm/is-even-ai@1.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "1.0.4", got.LatestVersion)
+	assert.Equal(t, "1.0.1", got.CurrentVersion)
+
+	registry.Wait()
+
+	assert.Equal(t, ports.CacheValidators{ETag: `"v1"`}, fake.lastValidators())
+
+	got, err = registry.LatestVersionFor(ctx, "pkg:npm/is-even-ai@1.0.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "1.0.5", got.LatestVersion)
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
+
+func TestRegistry_LatestVersionFor_QualifiersArePartOfCacheKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		packageType string
+		qualifier   string
+		packageUrls map[string]string
+	}{
+		{
+			name:        "Distribution release of OS package",
+			packageType: "deb",
+			qualifier:   "distro",
+			packageUrls: map[string]string{
+				"pkg:deb/debian/curl@7.88.1-10?distro=debian-11": "debian-11",
+				"pkg:deb/debian/curl@7.88.1-10?distro=debian-12": "debian-12",
+			},
+		},
+		{
+			name:        "Repository of generic package",
+			packageType: "generic",
+			qualifier:   "vcs_url",
+			packageUrls: map[string]string{
+				"pkg:generic/json@1.0.0?vcs_url=git%2Bhttps://github.com/nlohmann/json.git":               "git+https://github.com/nlohmann/json.git",
+				"pkg:generic/json@1.0.0?vcs_url=git%2Bhttps://github.com/open-source-parsers/jsoncpp.git": "git+https://github.com/open-source-parsers/jsoncpp.git",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testx.Context(t)
+			fake := &qualifierEchoChecker{packageType: tt.packageType, qualifier: tt.qualifier}
+			registry := checker.NewRegistry(db.NewMemoryKVStore(time.Hour))
+			registry.Register(fake)
+
+			for range 2 {
+				for packageUrl, want := range tt.packageUrls {
+					got, err := registry.LatestVersionFor(ctx, packageUrl)
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					assert.Equal(t, want, got.LatestVersion)
+				}
+			}
+
+			assert.EqualValues(t, len(tt.packageUrls), fake.calls.Load())
+		})
+	}
+}
+
+// qualifierEchoChecker reports the value of a qualifier as latest version.
+type qualifierEchoChecker struct {
+	packageType string
+	qualifier   string
+	calls       atomic.Int32
+}
+
+func (f *qualifierEchoChecker) LatestVersionFor(_ context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	f.calls.Add(1)
+
+	return &ports.PackageInfo{
+		Name:          packageUrl.Name,
+		LatestVersion: packageUrl.Qualifiers.Map()[f.qualifier],
+	}, nil
+}
+
+func (f *qualifierEchoChecker) SupportedPackageType() string {
+	return f.packageType
+}
+
+var _ ports.ConditionalUpdateChecker = (*fakeChecker)(nil)
+
+type fakeChecker struct {
+	info       *ports.PackageInfo
+	validators ports.CacheValidators
+	err        error
+
+	calls    atomic.Int32
+	lock     sync.Mutex
+	received ports.CacheValidators
+}
+
+func (f *fakeChecker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	info, _, err := f.LatestVersionIfModified(ctx, packageUrl, ports.CacheValidators{})
+	return info, err
+}
+
+func (f *fakeChecker) LatestVersionIfModified(
+	_ context.Context,
+	_ packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
+	f.calls.Add(1)
+
+	f.lock.Lock()
+	f.received = validators
+	f.lock.Unlock()
+
+	return f.info, f.validators, f.err
+}
+
+func (f *fakeChecker) SupportedPackageType() string {
+	return "npm"
+}
+
+func (f *fakeChecker) lastValidators() ports.CacheValidators {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.received
+}
+
+// recordingObserver records all observed lookups.
+type recordingObserver struct {
+	mu      sync.Mutex
+	lookups []observedLookup
+}
+
+type observedLookup struct {
+	packageType string
+	cacheStatus ports.CacheStatus
+	err         error
+}
+
+func (r *recordingObserver) ObserveLookup(packageType string, cacheStatus ports.CacheStatus, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lookups = append(r.lookups, observedLookup{packageType: packageType, cacheStatus: cacheStatus, err: err})
+}
@@ -0,0 +1,280 @@
+package rpm
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/klauspost/compress/zstd"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+const (
+	distroQualifier = "distro"
+	archQualifier   = "arch"
+	epochQualifier  = "epoch"
+	defaultArch     = "x86_64"
+	noArch          = "noarch"
+)
+
+// DefaultRepositories are URL templates of the repositories of the supported distributions indexed by the package URL namespace.
+// {release} is replaced with the release of the distro qualifier e.g. 40 for fedora-40, {major} with its major version
+// and {arch} with the architecture.
+var DefaultRepositories = map[string][]string{
+	"fedora": {
+		"https://dl.fedoraproject.org/pub/fedora/linux/releases/{release}/Everything/{arch}/os/",
+		"https://dl.fedoraproject.org/pub/fedora/linux/updates/{release}/Everything/{arch}/",
+	},
+	"rocky": {
+		"https://dl.rockylinux.org/pub/rocky/{major}/BaseOS/{arch}/os/",
+		"https://dl.rockylinux.org/pub/rocky/{major}/AppStream/{arch}/os/",
+	},
+	"almalinux": {
+		"https://repo.almalinux.org/almalinux/{major}/BaseOS/{arch}/os/",
+		"https://repo.almalinux.org/almalinux/{major}/AppStream/{arch}/os/",
+	},
+}
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker uses the DefaultRepositories - repositories replaces the templates of individual distributions.
+func NewChecker(client *http.Client, repositories map[string]string) Checker {
+	merged := make(map[string][]string, len(DefaultRepositories)+len(repositories))
+	for distribution, templates := range DefaultRepositories {
+		merged[distribution] = templates
+	}
+
+	for distribution, template := range repositories {
+		merged[distribution] = []string{template}
+	}
+
+	return Checker{
+		Client:       client,
+		Repositories: merged,
+		indices:      memo.New[packageIndex](),
+	}
+}
+
+// Checker looks up the latest version of RPM packages in the primary metadata of the repositories of the release
+// taken from the distro qualifier. Indices are downloaded once and shared by all lookups hence the checker has to be
+// created with NewChecker.
+type Checker struct {
+	Client       *http.Client
+	Repositories map[string][]string
+
+	indices *memo.Cache[packageIndex]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "rpm"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	templates, ok := c.Repositories[packageUrl.Namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w: no repository for distribution %q", ports.ErrNoCheckerForPackageType, packageUrl.Namespace)
+	}
+
+	qualifiers := packageUrl.Qualifiers.Map()
+
+	release := strings.TrimPrefix(qualifiers[distroQualifier], packageUrl.Namespace+"-")
+	if release == "" {
+		return nil, fmt.Errorf("%w: %s package without %s qualifier", ports.ErrNoCheckerForPackageType, packageUrl.Namespace, distroQualifier)
+	}
+
+	arch := qualifiers[archQualifier]
+	if arch == "" || arch == noArch {
+		arch = defaultArch
+	}
+
+	var (
+		latest evr
+		found  bool
+	)
+
+	for _, template := range templates {
+		repositoryURL := expandTemplate(template, release, arch)
+
+		index, err := c.indices.Get(ctx, repositoryURL, func(ctx context.Context) (packageIndex, error) {
+			return c.fetchIndex(ctx, repositoryURL)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candidateArch := range []string{arch, noArch} {
+			if v, ok := index[indexKey{name: packageUrl.Name, arch: candidateArch}]; ok && (!found || v.Compare(latest) > 0) {
+				latest, found = v, true
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s in %s %s", ports.ErrNoMatchingPackageFound, packageUrl.Name, packageUrl.Namespace, release)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  latest.String(),
+		PackageManager: "rpm",
+	}, nil
+}
+
+// fetchIndex resolves the location of the primary metadata via repomd.xml - missing repositories are treated as empty.
+func (c Checker) fetchIndex(ctx context.Context, repositoryURL string) (packageIndex, error) {
+	var md repomd
+
+	err := requests.
+		URL(httpx.BaseURL(repositoryURL)).
+		Path("repodata/repomd.xml").
+		Client(c.Client).
+		ToDeserializer(xml.Unmarshal, &md).
+		Fetch(ctx)
+
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		return packageIndex{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repository metadata of %s: %w", repositoryURL, err)
+	}
+
+	primaryLocation, ok := md.primaryLocation()
+	if !ok {
+		return nil, fmt.Errorf("repository %s has no primary metadata", repositoryURL)
+	}
+
+	var index packageIndex
+
+	err = requests.
+		URL(httpx.BaseURL(repositoryURL)).
+		Path(primaryLocation).
+		Client(c.Client).
+		Handle(func(resp *http.Response) error {
+			r, err := decompress(primaryLocation, resp.Body)
+			if err != nil {
+				return err
+			}
+
+			defer r.Close()
+
+			index, err = parsePrimary(r)
+			return err
+		}).
+		Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch primary metadata of %s: %w", repositoryURL, err)
+	}
+
+	return index, nil
+}
+
+func expandTemplate(template, release, arch string) string {
+	major, _, _ := strings.Cut(release, ".")
+
+	return strings.NewReplacer("{release}", release, "{major}", major, "{arch}", arch).Replace(template)
+}
+
+func decompress(location string, r io.Reader) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(location, ".gz"):
+		return gzip.NewReader(r)
+	case strings.HasSuffix(location, ".zst"):
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	case strings.HasSuffix(location, ".xml"):
+		return io.NopCloser(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression of %s", location)
+	}
+}
+
+type repomd struct {
+	Data []struct {
+		Type     string `xml:"type,attr"`
+		Location struct {
+			Href string `xml:"href,attr"`
+		} `xml:"location"`
+	} `xml:"data"`
+}
+
+func (m repomd) primaryLocation() (string, bool) {
+	for _, d := range m.Data {
+		if d.Type == "primary" && d.Location.Href != "" {
+			return d.Location.Href, true
+		}
+	}
+
+	return "", false
+}
+
+type indexKey struct {
+	name string
+	arch string
+}
+
+// packageIndex maps package names and architectures to their greatest version.
+type packageIndex map[indexKey]evr
+
+type primaryPackage struct {
+	Name    string `xml:"name"`
+	Arch    string `xml:"arch"`
+	Version struct {
+		Epoch   string `xml:"epoch,attr"`
+		Version string `xml:"ver,attr"`
+		Release string `xml:"rel,attr"`
+	} `xml:"version"`
+}
+
+// parsePrimary decodes the package elements one by one as primary metadata of large repositories takes hundreds of megabytes.
+func parsePrimary(r io.Reader) (packageIndex, error) {
+	var (
+		index   = make(packageIndex)
+		decoder = xml.NewDecoder(r)
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return index, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "package" {
+			continue
+		}
+
+		var pkg primaryPackage
+		if err := decoder.DecodeElement(&pkg, &start); err != nil {
+			return nil, err
+		}
+
+		v := parseEVR(pkg.Version.Version+"-"+pkg.Version.Release, pkg.Version.Epoch)
+		key := indexKey{name: pkg.Name, arch: pkg.Arch}
+
+		if current, ok := index[key]; !ok || v.Compare(current) > 0 {
+			index[key] = v
+		}
+	}
+}
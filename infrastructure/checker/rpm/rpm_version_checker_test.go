@@ -0,0 +1,153 @@
+package rpm_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/rpm"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/releases-repomd.xml
+	releasesRepomd []byte
+	//go:embed testdata/releases-primary.xml.gz
+	releasesPrimary []byte
+	//go:embed testdata/updates-repomd.xml
+	updatesRepomd []byte
+	//go:embed testdata/updates-primary.xml.zst
+	updatesPrimary []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	const (
+		releasesURL = "https://dl.fedoraproject.org/pub/fedora/linux/releases/40/Everything/x86_64/os/"
+		updatesURL  = "https://dl.fedoraproject.org/pub/fedora/linux/updates/40/Everything/x86_64/"
+	)
+
+	rules := make([]testx.ResponseRule, 0, 4)
+	for rawUrl, resp := range map[string][]byte{
+		releasesURL + "repodata/repomd.xml":              releasesRepomd,
+		releasesURL + "repodata/3b1f0c5e-primary.xml.gz": releasesPrimary,
+		updatesURL + "repodata/repomd.xml":               updatesRepomd,
+		updatesURL + "repodata/9a7d2e41-primary.xml.zst": updatesPrimary,
+	} {
+		rule, err := testx.NewSimpleUrlRule(rawUrl, resp)
+		if !assert.NoError(t, err) {
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	c := rpm.NewChecker(testx.MockHTTPClient(rules...), nil)
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package updated in updates repository",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/curl@8.6.0-1.fc40?arch=x86_64&distro=fedora-40",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "fedora",
+				Name:           "curl",
+				CurrentVersion: "8.6.0-1.fc40",
+				LatestVersion:  "8.6.0-8.fc40",
+				PackageManager: "rpm",
+			},
+		},
+		{
+			name: "Package with epoch qualifier",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/vim-minimal@9.1.083-1.fc40?arch=x86_64&distro=fedora-40&epoch=2",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "fedora",
+				Name:           "vim-minimal",
+				CurrentVersion: "9.1.083-1.fc40",
+				LatestVersion:  "2:9.1.452-1.fc40",
+				PackageManager: "rpm",
+			},
+		},
+		{
+			name: "Architecture independent package",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/tzdata@2024a-4.fc40?arch=noarch&distro=fedora-40",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "fedora",
+				Name:           "tzdata",
+				CurrentVersion: "2024a-4.fc40",
+				LatestVersion:  "2024a-5.fc40",
+				PackageManager: "rpm",
+			},
+		},
+		{
+			name: "Package only in release repository",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/bash@5.2.26-3.fc40?distro=fedora-40",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "fedora",
+				Name:           "bash",
+				CurrentVersion: "5.2.26-3.fc40",
+				LatestVersion:  "5.2.26-3.fc40",
+				PackageManager: "rpm",
+			},
+		},
+		{
+			name: "Unknown package",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/unknown@1.0-1.fc40?distro=fedora-40",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "Missing distro qualifier",
+			args: args{
+				packageUrl: "pkg:rpm/fedora/curl@8.6.0-1.fc40",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+		{
+			name: "Distribution without public repositories",
+			args: args{
+				packageUrl: "pkg:rpm/redhat/curl@7.76.1-29.el9?distro=rhel-9.4",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package rpm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// evr is the epoch, version and release of an RPM package.
+type evr struct {
+	Epoch   int
+	Version string
+	Release string
+}
+
+// parseEVR parses [epoch:]version[-release] - the epoch qualifier of package URLs takes precedence if set.
+func parseEVR(raw, epochQualifier string) evr {
+	var result evr
+
+	if e, rest, found := strings.Cut(raw, ":"); found {
+		if parsed, err := strconv.Atoi(e); err == nil {
+			result.Epoch, raw = parsed, rest
+		}
+	}
+
+	if parsed, err := strconv.Atoi(epochQualifier); err == nil {
+		result.Epoch = parsed
+	}
+
+	if i := strings.LastIndex(raw, "-"); i >= 0 {
+		result.Version, result.Release = raw[:i], raw[i+1:]
+	} else {
+		result.Version = raw
+	}
+
+	return result
+}
+
+// CompareVersions compares two [epoch:]version[-release] strings with the ordering of rpm.
+func CompareVersions(a, b string) int {
+	return parseEVR(a, "").Compare(parseEVR(b, ""))
+}
+
+func (v evr) String() string {
+	s := v.Version
+	if v.Release != "" {
+		s += "-" + v.Release
+	}
+
+	if v.Epoch != 0 {
+		s = strconv.Itoa(v.Epoch) + ":" + s
+	}
+
+	return s
+}
+
+func (v evr) Compare(other evr) int {
+	switch {
+	case v.Epoch < other.Epoch:
+		return -1
+	case v.Epoch > other.Epoch:
+		return 1
+	}
+
+	if c := rpmvercmp(v.Version, other.Version); c != 0 {
+		return c
+	}
+
+	// packages without release match any release
+	if v.Release == "" || other.Release == "" {
+		return 0
+	}
+
+	return rpmvercmp(v.Release, other.Release)
+}
+
+// rpmvercmp is a port of rpm's segment-wise version comparison including ~ for pre-releases
+// and ^ for snapshots.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for a != "" || b != "" {
+		a = strings.TrimLeftFunc(a, isSeparator)
+		b = strings.TrimLeftFunc(b, isSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		isNum := isDigit(rune(a[0]))
+
+		var aSegment, bSegment string
+		if isNum {
+			aSegment, a = cutRun(a, isDigit)
+			bSegment, b = cutRun(b, isDigit)
+		} else {
+			aSegment, a = cutRun(a, isLetter)
+			bSegment, b = cutRun(b, isLetter)
+		}
+
+		// segments of different types - numeric segments are newer
+		if bSegment == "" {
+			if isNum {
+				return 1
+			}
+
+			return -1
+		}
+
+		if isNum {
+			aSegment, bSegment = strings.TrimLeft(aSegment, "0"), strings.TrimLeft(bSegment, "0")
+			if len(aSegment) != len(bSegment) {
+				if len(aSegment) < len(bSegment) {
+					return -1
+				}
+
+				return 1
+			}
+		}
+
+		if c := strings.Compare(aSegment, bSegment); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func cutRun(s string, accept func(rune) bool) (run, rest string) {
+	i := strings.IndexFunc(s, func(r rune) bool { return !accept(r) })
+	if i < 0 {
+		return s, ""
+	}
+
+	return s[:i], s[i:]
+}
+
+func isSeparator(r rune) bool {
+	return !isDigit(r) && !isLetter(r) && r != '~' && r != '^'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
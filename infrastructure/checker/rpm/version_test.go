@@ -0,0 +1,51 @@
+package rpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rpmvercmp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0", b: "1.0", want: 0},
+		{a: "1.0", b: "2.0", want: -1},
+		{a: "2.0.1", b: "2.0", want: 1},
+		{a: "2.0.1a", b: "2.0.1", want: 1},
+		{a: "5.5p1", b: "5.5p2", want: -1},
+		{a: "5.5p10", b: "5.5p1", want: 1},
+		{a: "10xyz", b: "10.1xyz", want: -1},
+		{a: "xyz10", b: "xyz10.1", want: -1},
+		{a: "1.0", b: "1.0a", want: -1},
+		{a: "1b.fc17", b: "1.fc17", want: -1},
+		{a: "1.0~rc1", b: "1.0", want: -1},
+		{a: "1.0~rc1", b: "1.0~rc2", want: -1},
+		{a: "1.0^", b: "1.0", want: 1},
+		{a: "1.0^git1", b: "1.0.1", want: -1},
+		{a: "1.0^git1~pre", b: "1.0^git1", want: -1},
+		{a: "1.fc40", b: "2.fc40", want: -1},
+		{a: "008", b: "8", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, rpmvercmp(tt.a, tt.b))
+			assert.Equal(t, -tt.want, rpmvercmp(tt.b, tt.a))
+		})
+	}
+}
+
+func Test_evr_Compare(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1, parseEVR("1:1.0-1", "").Compare(parseEVR("2.0-1", "")))
+	assert.Equal(t, 0, parseEVR("1.0-1", "1").Compare(parseEVR("1:1.0-1", "")))
+	assert.Equal(t, -1, parseEVR("8.6.0-1.fc40", "").Compare(parseEVR("8.6.0-8.fc40", "")))
+	assert.Equal(t, "2:9.1.083-1.fc40", parseEVR("9.1.083-1.fc40", "2").String())
+}
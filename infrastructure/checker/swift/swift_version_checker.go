@@ -0,0 +1,78 @@
+package swift
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/gittag"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker clones packages via HTTPS from the host in their namespace unless hosts maps it to another base URL
+// e.g. github.com=https://git-mirror.example.com/github.
+func NewChecker(client *http.Client, hosts map[string]string) Checker {
+	return Checker{Client: client, Hosts: hosts}
+}
+
+// Checker resolves the latest version of Swift packages e.g. pkg:swift/github.com/apple/swift-argument-parser@1.2.3
+// from the semver tags of their git repository like the Swift Package Manager does.
+type Checker struct {
+	Client *http.Client
+	Hosts  map[string]string
+	// QualifierHosts restricts the hosts of namespaces not mapped by Hosts
+	QualifierHosts httpx.AllowedHosts
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "swift"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	host, owner, found := strings.Cut(packageUrl.Namespace, "/")
+	if !found || host == "" || owner == "" {
+		return nil, fmt.Errorf("%w: namespace of swift package %s has to consist of host and owner", ports.ErrInvalidPackageURL, packageUrl.Name)
+	}
+
+	baseURL := "https://" + host
+	if mapped, ok := c.Hosts[host]; ok {
+		baseURL = strings.TrimSuffix(mapped, "/")
+	} else if err := c.QualifierHosts.Check(baseURL); err != nil {
+		return nil, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+	}
+
+	repositoryURL := fmt.Sprintf("%s/%s/%s.git", baseURL, owner, packageUrl.Name)
+
+	tags, err := gittag.ListRemoteTags(ctx, c.Client, repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// only tags that are valid semantic versions are considered - optionally prefixed with v which is stripped as the
+	// Swift Package Manager reports the version without it
+	stable, preRelease := semverx.Latest(tags, func(tag string) (*semver.Version, error) {
+		return semver.StrictNewVersion(strings.TrimPrefix(tag, "v"))
+	})
+	if stable == "" {
+		return nil, fmt.Errorf("%w: no semver tag in %s", ports.ErrNoMatchingPackageFound, repositoryURL)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:               packageUrl.Namespace,
+		Name:                    packageUrl.Name,
+		CurrentVersion:          packageUrl.Version,
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		PackageManager:          "swift",
+	}, nil
+}
@@ -0,0 +1,148 @@
+package swift_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/swift"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+//go:embed testdata/swift-argument-parser_info_refs
+var argumentParserInfoRefs []byte
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		hosts          map[string]string
+		qualifierHosts httpx.AllowedHosts
+		clientConfig   map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Package hosted on GitHub",
+			args: args{
+				packageUrl: "pkg:swift/github.com/apple/swift-argument-parser@1.2.3",
+			},
+			fields: fields{
+				clientConfig: map[string][]byte{
+					"https://github.com/apple/swift-argument-parser.git/info/refs?service=git-upload-pack": argumentParserInfoRefs,
+				},
+			},
+			want: &ports.PackageInfo{
+				Namespace:               "github.com/apple",
+				Name:                    "swift-argument-parser",
+				CurrentVersion:          "1.2.3",
+				LatestVersion:           "1.5.1",
+				LatestPreReleaseVersion: "2.0.0-beta",
+				PackageManager:          "swift",
+			},
+		},
+		{
+			name: "Host mapped to mirror",
+			args: args{
+				packageUrl: "pkg:swift/github.com/apple/swift-argument-parser@1.5.1",
+			},
+			fields: fields{
+				hosts: map[string]string{"github.com": "https://git.example.com/mirror/"},
+				clientConfig: map[string][]byte{
+					"https://git.example.com/mirror/apple/swift-argument-parser.git/info/refs?service=git-upload-pack": argumentParserInfoRefs,
+				},
+			},
+			want: &ports.PackageInfo{
+				Namespace:               "github.com/apple",
+				Name:                    "swift-argument-parser",
+				CurrentVersion:          "1.5.1",
+				LatestVersion:           "1.5.1",
+				LatestPreReleaseVersion: "2.0.0-beta",
+				PackageManager:          "swift",
+			},
+		},
+		{
+			name: "Mapped host not on the allow-list",
+			args: args{
+				packageUrl: "pkg:swift/github.com/apple/swift-argument-parser@1.5.1",
+			},
+			fields: fields{
+				hosts:          map[string]string{"github.com": "https://git.example.com/mirror/"},
+				qualifierHosts: httpx.AllowHosts("gitlab.com"),
+				clientConfig: map[string][]byte{
+					"https://git.example.com/mirror/apple/swift-argument-parser.git/info/refs?service=git-upload-pack": argumentParserInfoRefs,
+				},
+			},
+			want: &ports.PackageInfo{
+				Namespace:               "github.com/apple",
+				Name:                    "swift-argument-parser",
+				CurrentVersion:          "1.5.1",
+				LatestVersion:           "1.5.1",
+				LatestPreReleaseVersion: "2.0.0-beta",
+				PackageManager:          "swift",
+			},
+		},
+		{
+			name: "Host not on the allow-list",
+			args: args{
+				packageUrl: "pkg:swift/169.254.169.254/latest/meta-data@1.0.0",
+			},
+			fields: fields{
+				qualifierHosts: httpx.AllowHosts("github.com"),
+			},
+			wantErr: httpx.ErrHostNotAllowed,
+		},
+		{
+			name: "Namespace without owner",
+			args: args{
+				packageUrl: "pkg:swift/github.com/swift-argument-parser@1.2.3",
+			},
+			wantErr: ports.ErrInvalidPackageURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var rules []testx.ResponseRule
+			for rawURL, response := range tt.fields.clientConfig {
+				rule, err := testx.NewSimpleUrlRule(rawURL, response)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				rules = append(rules, rule)
+			}
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c := swift.NewChecker(testx.MockHTTPClient(rules...), tt.fields.hosts)
+			c.QualifierHosts = tt.fields.qualifierHosts
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
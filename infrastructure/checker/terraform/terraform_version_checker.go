@@ -0,0 +1,239 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/memo"
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+const (
+	DefaultBaseURL = "https://registry.terraform.io"
+
+	// repositoryURLQualifier selects a private registry for a single provider or module
+	repositoryURLQualifier = "repository_url"
+
+	providersService = "providers.v1"
+	modulesService   = "modules.v1"
+)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker creates a checker for the registry at baseURL - tokens are the API tokens of private registries
+// indexed by their hostname e.g. app.terraform.io.
+func NewChecker(client *http.Client, baseURL string, tokens map[string]string) Checker {
+	return Checker{
+		Client:    client,
+		BaseURL:   baseURL,
+		Tokens:    tokens,
+		discovery: memo.New[services](),
+	}
+}
+
+// Checker implements the Terraform registry protocol for providers e.g. pkg:terraform/hashicorp/aws@5.31.0 and
+// modules e.g. pkg:terraform/terraform-aws-modules/vpc/aws@5.5.1 - private registries are selected with the
+// repository_url qualifier. The services of a registry are discovered once hence the checker has to be created with
+// NewChecker.
+type Checker struct {
+	Client  *http.Client
+	BaseURL string
+	Tokens  map[string]string
+	// QualifierHosts restricts the hosts the repository_url qualifier may select
+	QualifierHosts httpx.AllowedHosts
+
+	discovery *memo.Cache[services]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "terraform"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	registryURL := RegistryURL(c.BaseURL)
+	if repositoryURL := packageUrl.Qualifiers.Map()[repositoryURLQualifier]; repositoryURL != "" {
+		registryURL = RegistryURL(repositoryURL)
+		if err := c.QualifierHosts.Check(registryURL); err != nil {
+			return nil, fmt.Errorf("%w: %w", ports.ErrNoCheckerForPackageType, err)
+		}
+	}
+
+	discovered, err := c.discovery.Get(ctx, registryURL, func(ctx context.Context) (services, error) {
+		return c.discover(ctx, registryURL)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+
+	switch strings.Count(packageUrl.Namespace, "/") {
+	case 0:
+		versions, err = c.providerVersions(ctx, discovered, packageUrl.Namespace, packageUrl.Name)
+	case 1:
+		versions, err = c.moduleVersions(ctx, discovered, packageUrl.Namespace, packageUrl.Name)
+	default:
+		return nil, fmt.Errorf("%w: %s/%s is neither a provider nor a module address", ports.ErrInvalidPackageURL, packageUrl.Namespace, packageUrl.Name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stable, preRelease := semverx.Latest(versions, semver.NewVersion)
+	if stable == "" {
+		return nil, fmt.Errorf("%w: no version of %s/%s", ports.ErrNoMatchingPackageFound, packageUrl.Namespace, packageUrl.Name)
+	}
+
+	return &ports.PackageInfo{
+		Namespace:               packageUrl.Namespace,
+		Name:                    packageUrl.Name,
+		CurrentVersion:          packageUrl.Version,
+		LatestVersion:           stable,
+		LatestPreReleaseVersion: preRelease,
+		PackageManager:          "terraform",
+	}, nil
+}
+
+// RegistryURL accepts registry URLs as well as hostnames as Terraform addresses registries by hostname.
+func RegistryURL(hostOrURL string) string {
+	if !strings.Contains(hostOrURL, "://") {
+		return "https://" + hostOrURL
+	}
+
+	return hostOrURL
+}
+
+// providerVersions lists the versions of the provider namespace/type.
+func (c Checker) providerVersions(ctx context.Context, discovered services, namespace, providerType string) ([]string, error) {
+	serviceURL, err := discovered.resolve(providersService)
+	if err != nil {
+		return nil, err
+	}
+
+	var result providerVersionsResponse
+	if err := c.fetch(ctx, serviceURL, path.Join(namespace, providerType, "versions"), &result); err != nil {
+		if requests.HasStatusErr(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("%w: provider %s/%s", ports.ErrNoMatchingPackageFound, namespace, providerType)
+		}
+
+		return nil, fmt.Errorf("failed to list versions of provider %s/%s: %w", namespace, providerType, err)
+	}
+
+	versions := make([]string, 0, len(result.Versions))
+	for _, v := range result.Versions {
+		versions = append(versions, v.Version)
+	}
+
+	return versions, nil
+}
+
+// moduleVersions lists the versions of the module namespace/name/system - the namespace of the package URL
+// consists of the module namespace and name.
+func (c Checker) moduleVersions(ctx context.Context, discovered services, namespace, system string) ([]string, error) {
+	serviceURL, err := discovered.resolve(modulesService)
+	if err != nil {
+		return nil, err
+	}
+
+	var result moduleVersionsResponse
+	if err := c.fetch(ctx, serviceURL, path.Join(namespace, system, "versions"), &result); err != nil {
+		if requests.HasStatusErr(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("%w: module %s/%s", ports.ErrNoMatchingPackageFound, namespace, system)
+		}
+
+		return nil, fmt.Errorf("failed to list versions of module %s/%s: %w", namespace, system, err)
+	}
+
+	var versions []string
+	for _, m := range result.Modules {
+		for _, v := range m.Versions {
+			versions = append(versions, v.Version)
+		}
+	}
+
+	return versions, nil
+}
+
+func (c Checker) fetch(ctx context.Context, serviceURL *url.URL, relativePath string, v any) error {
+	return requests.
+		URL(httpx.BaseURL(serviceURL.String())).
+		Path(relativePath).
+		Client(c.Client).
+		HeaderOptional("Authorization", c.bearer(serviceURL.Hostname())).
+		ToJSON(v).
+		Fetch(ctx)
+}
+
+// discover reads the services of a registry from /.well-known/terraform.json.
+func (c Checker) discover(ctx context.Context, registryURL string) (services, error) {
+	base, err := url.Parse(httpx.BaseURL(registryURL))
+	if err != nil {
+		return services{}, fmt.Errorf("%w: invalid registry URL %q: %v", ports.ErrInvalidPackageURL, registryURL, err)
+	}
+
+	discovered := services{base: base}
+
+	err = requests.
+		URL(base.String()).
+		Path(".well-known/terraform.json").
+		Client(c.Client).
+		HeaderOptional("Authorization", c.bearer(base.Hostname())).
+		ToJSON(&discovered.endpoints).
+		Fetch(ctx)
+	if err != nil {
+		return services{}, fmt.Errorf("failed to discover services of %s: %w", base.Host, err)
+	}
+
+	return discovered, nil
+}
+
+func (c Checker) bearer(host string) string {
+	if token := c.Tokens[host]; token != "" {
+		return "Bearer " + token
+	}
+
+	return ""
+}
+
+// services are the endpoints a registry announces via service discovery e.g. "providers.v1": "/v1/providers/".
+type services struct {
+	base      *url.URL
+	endpoints map[string]any
+}
+
+// resolve returns the URL of a service - relative URLs are resolved against the registry.
+func (s services) resolve(service string) (*url.URL, error) {
+	raw, ok := s.endpoints[service].(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("%w: %s does not offer the %s service", ports.ErrNoCheckerForPackageType, s.base.Host, service)
+	}
+
+	return s.base.Parse(raw)
+}
+
+type providerVersionsResponse struct {
+	Versions []struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+}
+
+type moduleVersionsResponse struct {
+	Modules []struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"modules"`
+}
@@ -0,0 +1,204 @@
+package terraform_test
+
+import (
+	_ "embed"
+	"net/http"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/terraform"
+	"github.com/prskr/aucs/internal/httpx"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/terraform.json
+	publicDiscovery []byte
+	//go:embed testdata/private-terraform.json
+	privateDiscovery []byte
+	//go:embed testdata/hashicorp-aws-versions.json
+	awsProviderVersions []byte
+	//go:embed testdata/terraform-aws-modules-vpc-aws-versions.json
+	vpcModuleVersions []byte
+	//go:embed testdata/acme-network-aws-versions.json
+	privateModuleVersions []byte
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Provider",
+			args: args{
+				packageUrl: "pkg:terraform/hashicorp/aws@5.31.0",
+			},
+			want: &ports.PackageInfo{
+				Namespace:               "hashicorp",
+				Name:                    "aws",
+				CurrentVersion:          "5.31.0",
+				LatestVersion:           "5.80.0",
+				LatestPreReleaseVersion: "6.0.0-beta1",
+				PackageManager:          "terraform",
+			},
+		},
+		{
+			name: "Module",
+			args: args{
+				packageUrl: "pkg:terraform/terraform-aws-modules/vpc/aws@5.5.1",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "terraform-aws-modules/vpc",
+				Name:           "aws",
+				CurrentVersion: "5.5.1",
+				LatestVersion:  "5.16.0",
+				PackageManager: "terraform",
+			},
+		},
+		{
+			name: "Module in private registry",
+			args: args{
+				packageUrl: "pkg:terraform/acme/network/aws@1.2.0?repository_url=app.example.com",
+			},
+			want: &ports.PackageInfo{
+				Namespace:      "acme/network",
+				Name:           "aws",
+				CurrentVersion: "1.2.0",
+				LatestVersion:  "1.3.0",
+				PackageManager: "terraform",
+			},
+		},
+		{
+			name: "Unknown provider",
+			args: args{
+				packageUrl: "pkg:terraform/hashicorp/unknown@1.0.0",
+			},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "Invalid address",
+			args: args{
+				packageUrl: "pkg:terraform/a/b/c/d@1.0.0",
+			},
+			wantErr: ports.ErrInvalidPackageURL,
+		},
+	}
+
+	var rules []testx.ResponseRule
+
+	for _, r := range []struct {
+		url      string
+		response []byte
+		token    string
+	}{
+		{url: "https://registry.terraform.io/.well-known/terraform.json", response: publicDiscovery},
+		{url: "https://registry.terraform.io/v1/providers/hashicorp/aws/versions", response: awsProviderVersions},
+		{url: "https://registry.terraform.io/v1/modules/terraform-aws-modules/vpc/aws/versions", response: vpcModuleVersions},
+		{url: "https://app.example.com/.well-known/terraform.json", response: privateDiscovery},
+		{url: "https://tf-api.example.com/api/registry/v1/modules/acme/network/aws/versions", response: privateModuleVersions, token: "Bearer s3cr3t"},
+	} {
+		rule, err := testx.NewSimpleUrlRule(r.url, r.response)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		rules = append(rules, authorizedRule{SimpleUrlRule: rule, authorization: r.token})
+	}
+
+	notFound, err := testx.NewSimpleUrlRule("https://registry.terraform.io/v1/providers/hashicorp/unknown/versions", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	notFound.StatusCode = http.StatusNotFound
+	rules = append(rules, notFound)
+
+	c := terraform.NewChecker(
+		testx.MockHTTPClient(rules...),
+		terraform.DefaultBaseURL,
+		map[string]string{"tf-api.example.com": "s3cr3t"},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := c.LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTokensFromEnv(t *testing.T) {
+	t.Parallel()
+
+	got := terraform.TokensFromEnv([]string{
+		"HOME=/root",
+		"TF_TOKEN_app_terraform_io=abc",
+		"TF_TOKEN_tf__registry_example_com=def",
+		"TF_TOKEN_empty_example_com=",
+	})
+
+	assert.Equal(t, map[string]string{
+		"app.terraform.io":        "abc",
+		"tf-registry.example.com": "def",
+	}, got)
+}
+
+// authorizedRule only matches requests carrying the expected Authorization header.
+type authorizedRule struct {
+	testx.SimpleUrlRule
+	authorization string
+}
+
+func (a authorizedRule) Matches(req *http.Request) bool {
+	return a.SimpleUrlRule.Matches(req) && req.Header.Get("Authorization") == a.authorization
+}
+
+func TestChecker_LatestVersionFor_QualifierHosts(t *testing.T) {
+	t.Parallel()
+
+	c := terraform.NewChecker(testx.MockHTTPClient(), terraform.DefaultBaseURL, nil)
+	c.QualifierHosts = httpx.AllowHosts("example.com")
+
+	for _, packageUrl := range []string{
+		"pkg:terraform/acme/network/aws@1.2.0?repository_url=internal.example.org",
+		"pkg:terraform/acme/network/aws@1.2.0?repository_url=http://127.0.0.1:8080",
+	} {
+		t.Run(packageUrl, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = c.LatestVersionFor(testx.Context(t), purl)
+			assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+			assert.ErrorIs(t, err, ports.ErrNoCheckerForPackageType)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package terraform
+
+import "strings"
+
+const tokenEnvPrefix = "TF_TOKEN_"
+
+// TokensFromEnv reads API tokens from TF_TOKEN_<host> variables as the Terraform CLI does - periods of the hostname
+// are encoded as underscores and dashes as double underscores e.g. TF_TOKEN_app_terraform_io or
+// TF_TOKEN_tf__registry_example_com for tf-registry.example.com.
+func TokensFromEnv(environ []string) map[string]string {
+	tokens := make(map[string]string)
+
+	for _, entry := range environ {
+		name, token, found := strings.Cut(entry, "=")
+		if !found || token == "" || !strings.HasPrefix(name, tokenEnvPrefix) {
+			continue
+		}
+
+		host := strings.TrimPrefix(name, tokenEnvPrefix)
+		host = strings.ReplaceAll(host, "__", "-")
+		host = strings.ReplaceAll(host, "_", ".")
+
+		tokens[strings.ToLower(host)] = token
+	}
+
+	return tokens
+}
@@ -0,0 +1,174 @@
+package vcpkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/memo"
+)
+
+const (
+	defaultBaseline = "default"
+
+	// baselineTTL is short as the checkout might be updated while the server is running
+	baselineTTL = time.Minute
+)
+
+// portNamePattern is the grammar of vcpkg port names - it also prevents names from escaping the registry checkout
+var portNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var _ ports.UpdateChecker = (*Checker)(nil)
+
+// NewChecker reads the registry checked out at root e.g. $VCPKG_ROOT or a clone of a custom git registry.
+func NewChecker(root string) Checker {
+	return Checker{
+		Root:      root,
+		baselines: &memo.Cache[baseline]{TTL: baselineTTL},
+	}
+}
+
+// Checker looks up the latest version of vcpkg ports in the default baseline of the versions/baseline.json of a
+// registry checkout - ports missing in the baseline are looked up in their version database e.g. versions/z-/zlib.json.
+// Versions with port version are reported as version#port-version like vcpkg does.
+type Checker struct {
+	Root string
+
+	baselines *memo.Cache[baseline]
+}
+
+// SupportedPackageType implements ports.UpdateChecker.
+func (Checker) SupportedPackageType() string {
+	return "vcpkg"
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (c Checker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (*ports.PackageInfo, error) {
+	if c.Root == "" {
+		return nil, fmt.Errorf("%w: no vcpkg registry checkout configured", ports.ErrNoCheckerForPackageType)
+	}
+
+	if !portNamePattern.MatchString(packageUrl.Name) {
+		return nil, fmt.Errorf("%w: invalid vcpkg port name %q", ports.ErrInvalidPackageURL, packageUrl.Name)
+	}
+
+	baselinePath := filepath.Join(c.Root, "versions", "baseline.json")
+
+	entries, err := c.baselines.Get(ctx, baselinePath, func(context.Context) (baseline, error) {
+		return readBaseline(baselinePath)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := entries[packageUrl.Name]
+	if !ok {
+		if entry, err = c.latestFromVersionDatabase(packageUrl.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ports.PackageInfo{
+		Namespace:      packageUrl.Namespace,
+		Name:           packageUrl.Name,
+		CurrentVersion: packageUrl.Version,
+		LatestVersion:  entry.String(),
+		PackageManager: "vcpkg",
+	}, nil
+}
+
+// latestFromVersionDatabase reads the newest entry of the version database of a port which is listed first.
+// The port name has to be validated before as it's part of the path.
+func (c Checker) latestFromVersionDatabase(port string) (versionEntry, error) {
+	databasePath := filepath.Join(c.Root, "versions", port[:1]+"-", port+".json")
+
+	raw, err := os.ReadFile(databasePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return versionEntry{}, fmt.Errorf("%w: %s", ports.ErrNoMatchingPackageFound, port)
+	} else if err != nil {
+		return versionEntry{}, fmt.Errorf("failed to read version database of %s: %w", port, err)
+	}
+
+	var database versionDatabase
+	if err := json.Unmarshal(raw, &database); err != nil {
+		return versionEntry{}, fmt.Errorf("failed to parse %s: %w", databasePath, err)
+	}
+
+	if len(database.Versions) == 0 {
+		return versionEntry{}, fmt.Errorf("%w: no version of %s", ports.ErrNoMatchingPackageFound, port)
+	}
+
+	return database.Versions[0].versionEntry(), nil
+}
+
+// baseline maps the ports of the default baseline to their version.
+type baseline map[string]versionEntry
+
+func readBaseline(baselinePath string) (baseline, error) {
+	raw, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vcpkg baseline: %w", err)
+	}
+
+	var baselines map[string]map[string]struct {
+		Baseline    string `json:"baseline"`
+		PortVersion int    `json:"port-version"`
+	}
+
+	if err := json.Unmarshal(raw, &baselines); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", baselinePath, err)
+	}
+
+	ports := make(baseline, len(baselines[defaultBaseline]))
+	for name, entry := range baselines[defaultBaseline] {
+		ports[name] = versionEntry{Version: entry.Baseline, PortVersion: entry.PortVersion}
+	}
+
+	return ports, nil
+}
+
+type versionEntry struct {
+	Version     string
+	PortVersion int
+}
+
+func (v versionEntry) String() string {
+	if v.PortVersion == 0 {
+		return v.Version
+	}
+
+	return v.Version + "#" + strconv.Itoa(v.PortVersion)
+}
+
+type versionDatabase struct {
+	Versions []databaseEntry `json:"versions"`
+}
+
+// databaseEntry has exactly one of the version fields set depending on the versioning scheme of the port.
+type databaseEntry struct {
+	Version       string `json:"version"`
+	VersionSemver string `json:"version-semver"`
+	VersionDate   string `json:"version-date"`
+	VersionString string `json:"version-string"`
+	PortVersion   int    `json:"port-version"`
+}
+
+func (e databaseEntry) versionEntry() versionEntry {
+	for _, v := range []string{e.Version, e.VersionSemver, e.VersionDate, e.VersionString} {
+		if v != "" {
+			return versionEntry{Version: v, PortVersion: e.PortVersion}
+		}
+	}
+
+	return versionEntry{PortVersion: e.PortVersion}
+}
@@ -0,0 +1,121 @@
+package vcpkg_test
+
+import (
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker/vcpkg"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestChecker_LatestVersionFor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		packageUrl string
+	}
+	type fields struct {
+		root string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		fields  fields
+		want    *ports.PackageInfo
+		wantErr error
+	}{
+		{
+			name: "Port in baseline",
+			args: args{
+				packageUrl: "pkg:vcpkg/zlib@1.2.13",
+			},
+			fields: fields{root: "testdata/registry"},
+			want: &ports.PackageInfo{
+				Name:           "zlib",
+				CurrentVersion: "1.2.13",
+				LatestVersion:  "1.3.1",
+				PackageManager: "vcpkg",
+			},
+		},
+		{
+			name: "Port version",
+			args: args{
+				packageUrl: "pkg:vcpkg/fmt@11.0.2",
+			},
+			fields: fields{root: "testdata/registry"},
+			want: &ports.PackageInfo{
+				Name:           "fmt",
+				CurrentVersion: "11.0.2",
+				LatestVersion:  "11.0.2#1",
+				PackageManager: "vcpkg",
+			},
+		},
+		{
+			name: "Port missing in baseline",
+			args: args{
+				packageUrl: "pkg:vcpkg/zstd@1.5.5",
+			},
+			fields: fields{root: "testdata/registry"},
+			want: &ports.PackageInfo{
+				Name:           "zstd",
+				CurrentVersion: "1.5.5",
+				LatestVersion:  "1.5.6#2",
+				PackageManager: "vcpkg",
+			},
+		},
+		{
+			name: "Unknown port",
+			args: args{
+				packageUrl: "pkg:vcpkg/unknown@1.0.0",
+			},
+			fields:  fields{root: "testdata/registry"},
+			wantErr: ports.ErrNoMatchingPackageFound,
+		},
+		{
+			name: "Port name escaping the checkout",
+			args: args{
+				packageUrl: "pkg:vcpkg/..%2F..%2F..%2Foutside@1.0.0",
+			},
+			fields:  fields{root: "testdata/registry"},
+			wantErr: ports.ErrInvalidPackageURL,
+		},
+		{
+			name: "Port name not matching grammar",
+			args: args{
+				packageUrl: "pkg:vcpkg/Zlib_@1.2.13",
+			},
+			fields:  fields{root: "testdata/registry"},
+			wantErr: ports.ErrInvalidPackageURL,
+		},
+		{
+			name: "No checkout configured",
+			args: args{
+				packageUrl: "pkg:vcpkg/zlib@1.2.13",
+			},
+			wantErr: ports.ErrNoCheckerForPackageType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			purl, err := packageurl.FromString(tt.args.packageUrl)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := vcpkg.NewChecker(tt.fields.root).LatestVersionFor(testx.Context(t), purl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -4,12 +4,24 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/dgraph-io/badger/v4"
+
 	"github.com/prskr/aucs/core/ports"
 )
 
+// metaTimestamped marks values that are prefixed with the time they were stored at.
+// Entries written by older versions don't carry the flag and are returned as is.
+const metaTimestamped byte = 1 << 0
+
+const badgerLockRetryInterval = 250 * time.Millisecond
+
+var ErrDatabaseLocked = errors.New("database is locked by another process")
+
 var _ ports.KeyValueStore = (*BadgerKVStore)(nil)
 
 func NewBadgerKVStore(dbPath string, ttl time.Duration) (*BadgerKVStore, error) {
@@ -19,12 +31,32 @@ func NewBadgerKVStore(dbPath string, ttl time.Duration) (*BadgerKVStore, error)
 
 	badgerDB, err := badger.Open(opts)
 	if err != nil {
+		if isBadgerLockError(err) {
+			return nil, fmt.Errorf("%w: %w", ErrDatabaseLocked, err)
+		}
+
 		return nil, err
 	}
 
 	return &BadgerKVStore{DB: badgerDB, TTL: ttl}, nil
 }
 
+// WaitForBadgerKVStore retries to open the database until the directory lock
+// held by another process is released or the timeout elapsed.
+func WaitForBadgerKVStore(dbPath string, ttl, timeout time.Duration) (*BadgerKVStore, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		store, err := NewBadgerKVStore(dbPath, ttl)
+		if err == nil || !errors.Is(err, ErrDatabaseLocked) || time.Now().After(deadline) {
+			return store, err
+		}
+
+		slog.Debug("Waiting for database lock to be released", slog.String("path", dbPath))
+		time.Sleep(min(badgerLockRetryInterval, time.Until(deadline)))
+	}
+}
+
 type BadgerKVStore struct {
 	TTL time.Duration
 	DB  *badger.DB
@@ -40,7 +72,8 @@ func (b *BadgerKVStore) Get(ctx context.Context, key []byte) ([]byte, error) {
 		}
 
 		return item.Value(func(val []byte) error {
-			value = bytes.Clone(val)
+			_, payload := decodeBadgerValue(item.UserMeta(), val)
+			value = bytes.Clone(payload)
 			return nil
 		})
 	})
@@ -52,10 +85,81 @@ func (b *BadgerKVStore) Get(ctx context.Context, key []byte) ([]byte, error) {
 	return value, err
 }
 
+// Iterate implements ports.KeyValueStore.
+func (b *BadgerKVStore) Iterate(ctx context.Context, prefix []byte, fn func(entry ports.KeyValueEntry) error) error {
+	return b.DB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item := it.Item()
+			entry := ports.KeyValueEntry{
+				Key: item.KeyCopy(nil),
+			}
+
+			if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+				entry.ExpiresAt = time.Unix(int64(expiresAt), 0)
+			}
+
+			err := item.Value(func(val []byte) error {
+				var payload []byte
+				entry.StoredAt, payload = decodeBadgerValue(item.UserMeta(), val)
+				entry.Value = bytes.Clone(payload)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // Put implements ports.KeyValueStore.
 func (b *BadgerKVStore) Put(ctx context.Context, key []byte, value []byte) error {
+	now := time.Now()
+
+	return b.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       key,
+		Value:     value,
+		StoredAt:  now,
+		ExpiresAt: expiryFor(now, b.TTL),
+	})
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (b *BadgerKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) error {
+	storedAt := entry.StoredAt
+	if storedAt.IsZero() {
+		storedAt = time.Now()
+	}
+
+	badgerEntry := badger.NewEntry(entry.Key, encodeTimestampedValue(storedAt, entry.Value)).WithMeta(metaTimestamped)
+	if !entry.ExpiresAt.IsZero() {
+		badgerEntry.ExpiresAt = uint64(entry.ExpiresAt.Unix())
+	}
+
 	return b.DB.Update(func(txn *badger.Txn) error {
-		return txn.SetEntry(badger.NewEntry(key, value).WithTTL(b.TTL))
+		return txn.SetEntry(badgerEntry)
+	})
+}
+
+// Delete implements ports.KeyValueStore.
+func (b *BadgerKVStore) Delete(ctx context.Context, key []byte) error {
+	return b.DB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
 	})
 }
 
@@ -63,3 +167,17 @@ func (b *BadgerKVStore) Put(ctx context.Context, key []byte, value []byte) error
 func (b *BadgerKVStore) Close() error {
 	return b.DB.Close()
 }
+
+func decodeBadgerValue(meta byte, raw []byte) (storedAt time.Time, value []byte) {
+	if meta&metaTimestamped == 0 {
+		return time.Time{}, raw
+	}
+
+	return decodeTimestampedValue(raw)
+}
+
+// isBadgerLockError detects whether opening the database failed because another process holds the directory lock.
+// Badger doesn't expose a sentinel error and the underlying errno differs between platforms.
+func isBadgerLockError(err error) bool {
+	return strings.Contains(err.Error(), "Cannot acquire directory lock")
+}
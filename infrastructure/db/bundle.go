@@ -0,0 +1,182 @@
+package db
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const (
+	bundleFormat  = "aucs-cache-bundle"
+	bundleVersion = 1
+)
+
+var (
+	ErrUnknownBundleFormat      = errors.New("unknown cache bundle format")
+	ErrUnsupportedBundleVersion = errors.New("unsupported cache bundle version")
+)
+
+// bundleHeader is the first JSON document of every bundle.
+type bundleHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// bundleEntry is the on-disk representation of a single ports.KeyValueEntry.
+type bundleEntry struct {
+	Key       []byte     `json:"key"`
+	Value     []byte     `json:"value"`
+	StoredAt  *time.Time `json:"stored_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ImportOptions struct {
+	// MaxAge skips entries stored longer ago than MaxAge - zero disables the cutoff.
+	MaxAge time.Duration
+	// Now is the reference time to evaluate expiry and MaxAge against - defaults to time.Now().
+	Now time.Time
+}
+
+type ImportStats struct {
+	Imported int
+	Expired  int
+	TooOld   int
+	Outdated int
+}
+
+// ExportBundle writes all live entries of kv as gzip compressed stream of JSON documents to w.
+// It returns the number of exported entries.
+func ExportBundle(ctx context.Context, kv ports.KeyValueIterator, w io.Writer) (exported int, err error) {
+	gzipWriter := gzip.NewWriter(w)
+	defer func() {
+		err = errors.Join(err, gzipWriter.Close())
+	}()
+
+	encoder := json.NewEncoder(gzipWriter)
+
+	header := bundleHeader{
+		Format:    bundleFormat,
+		Version:   bundleVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := encoder.Encode(header); err != nil {
+		return 0, fmt.Errorf("failed to write bundle header: %w", err)
+	}
+
+	err = kv.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		exported++
+		return encoder.Encode(bundleEntry{
+			Key:       entry.Key,
+			Value:     entry.Value,
+			StoredAt:  optionalTime(entry.StoredAt),
+			ExpiresAt: optionalTime(entry.ExpiresAt),
+		})
+	})
+
+	return exported, err
+}
+
+// ImportBundle merges all entries of the bundle read from r into kv.
+// Entries keep their original expiry, already expired entries, entries older than opts.MaxAge
+// and entries older than the ones already present in kv are skipped.
+func ImportBundle(ctx context.Context, kv ports.KeyValueStore, r io.Reader, opts ImportOptions) (stats ImportStats, err error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	gzipReader, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return stats, fmt.Errorf("failed to open bundle: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, gzipReader.Close())
+	}()
+
+	decoder := json.NewDecoder(gzipReader)
+
+	var header bundleHeader
+	if err := decoder.Decode(&header); err != nil {
+		return stats, fmt.Errorf("failed to read bundle header: %w", err)
+	}
+
+	if header.Format != bundleFormat {
+		return stats, fmt.Errorf("%w: %s", ErrUnknownBundleFormat, header.Format)
+	}
+
+	if header.Version != bundleVersion {
+		return stats, fmt.Errorf("%w: %d", ErrUnsupportedBundleVersion, header.Version)
+	}
+
+	existing := make(map[string]time.Time)
+	err = kv.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		existing[string(entry.Key)] = entry.StoredAt
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to index existing entries: %w", err)
+	}
+
+	for {
+		var entry bundleEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return stats, nil
+			}
+
+			return stats, fmt.Errorf("failed to read bundle entry: %w", err)
+		}
+
+		kvEntry := ports.KeyValueEntry{
+			Key:   entry.Key,
+			Value: entry.Value,
+		}
+
+		if entry.StoredAt != nil {
+			kvEntry.StoredAt = *entry.StoredAt
+		}
+
+		if entry.ExpiresAt != nil {
+			kvEntry.ExpiresAt = *entry.ExpiresAt
+		}
+
+		switch {
+		case !kvEntry.ExpiresAt.IsZero() && !kvEntry.ExpiresAt.After(opts.Now):
+			stats.Expired++
+			continue
+		case opts.MaxAge > 0 && !kvEntry.StoredAt.IsZero() && kvEntry.StoredAt.Before(opts.Now.Add(-opts.MaxAge)):
+			stats.TooOld++
+			continue
+		}
+
+		if storedAt, ok := existing[string(kvEntry.Key)]; ok && !storedAt.Before(kvEntry.StoredAt) {
+			stats.Outdated++
+			continue
+		}
+
+		if err := kv.PutEntry(ctx, kvEntry); err != nil {
+			return stats, fmt.Errorf("failed to import entry %s: %w", kvEntry.Key, err)
+		}
+
+		stats.Imported++
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	t = t.UTC()
+
+	return &t
+}
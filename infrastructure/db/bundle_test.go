@@ -0,0 +1,284 @@
+package db_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	now := time.Now()
+
+	source := inMemoryBadgerStore(t)
+	entries := bundleEntries(now)
+	if !putEntries(t, source, entries...) {
+		return
+	}
+
+	var bundle bytes.Buffer
+	exported, err := db.ExportBundle(ctx, source, &bundle)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, len(entries), exported)
+
+	target := inMemoryBadgerStore(t)
+	stats, err := db.ImportBundle(ctx, target, &bundle, db.ImportOptions{MaxAge: 24 * time.Hour})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, db.ImportStats{Imported: 1, TooOld: 1}, stats)
+
+	imported := allEntries(t, target)
+	if !assert.Len(t, imported, 1) {
+		return
+	}
+
+	assert.Equal(t, entries[0].Key, imported[0].Key)
+	assert.Equal(t, entries[0].Value, imported[0].Value)
+	assert.Equal(t, entries[0].ExpiresAt.Unix(), imported[0].ExpiresAt.Unix())
+	assert.True(t, entries[0].StoredAt.Equal(imported[0].StoredAt))
+}
+
+func TestExportImportBundle_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	now := time.Now()
+
+	source := inMemoryBadgerStore(t)
+	entries := bundleEntries(now)
+	if !putEntries(t, source, entries...) {
+		return
+	}
+
+	var bundle bytes.Buffer
+	if _, err := db.ExportBundle(ctx, source, &bundle); !assert.NoError(t, err) {
+		return
+	}
+
+	target := inMemoryBadgerStore(t)
+	stats, err := db.ImportBundle(ctx, target, &bundle, db.ImportOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, db.ImportStats{Imported: len(entries)}, stats)
+
+	imported := allEntries(t, target)
+	if !assert.Len(t, imported, len(entries)) {
+		return
+	}
+
+	for i, want := range entries {
+		assert.Equal(t, want.Key, imported[i].Key)
+		assert.Equal(t, want.Value, imported[i].Value)
+		assert.True(t, want.StoredAt.Equal(imported[i].StoredAt))
+		assert.Equal(t, want.ExpiresAt.Unix(), imported[i].ExpiresAt.Unix())
+	}
+}
+
+func TestImportBundle_SkipsExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	now := time.Now()
+
+	source := inMemoryBadgerStore(t)
+	if !putEntries(t, source, bundleEntries(now)...) {
+		return
+	}
+
+	var bundle bytes.Buffer
+	if _, err := db.ExportBundle(ctx, source, &bundle); !assert.NoError(t, err) {
+		return
+	}
+
+	// the bundle is imported after all entries expired
+	target := inMemoryBadgerStore(t)
+	stats, err := db.ImportBundle(ctx, target, &bundle, db.ImportOptions{Now: now.Add(6 * time.Hour)})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, db.ImportStats{Expired: 2}, stats)
+	assert.Empty(t, allEntries(t, target))
+}
+
+func TestImportBundle_KeepsNewerEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	now := time.Now()
+
+	source := inMemoryBadgerStore(t)
+	entries := bundleEntries(now)
+	if !putEntries(t, source, entries...) {
+		return
+	}
+
+	var bundle bytes.Buffer
+	if _, err := db.ExportBundle(ctx, source, &bundle); !assert.NoError(t, err) {
+		return
+	}
+
+	target := inMemoryBadgerStore(t)
+	newer := ports.KeyValueEntry{Key: entries[0].Key, Value: []byte(`{"LatestVersion":"1.0.6"}`), StoredAt: now, ExpiresAt: now.Add(6 * time.Hour)}
+	older := ports.KeyValueEntry{Key: entries[1].Key, Value: []byte(`{"LatestVersion":"2.31.0"}`), StoredAt: now.Add(-72 * time.Hour), ExpiresAt: now.Add(time.Hour)}
+	if !putEntries(t, target, newer, older) {
+		return
+	}
+
+	stats, err := db.ImportBundle(ctx, target, &bundle, db.ImportOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, db.ImportStats{Imported: 1, Outdated: 1}, stats)
+
+	imported := allEntries(t, target)
+	if assert.Len(t, imported, 2) {
+		assert.Equal(t, newer.Value, imported[0].Value)
+		assert.Equal(t, entries[1].Value, imported[1].Value)
+	}
+}
+
+func TestImportBundle_InvalidBundle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		bundle  func(tb testing.TB) []byte
+		wantErr error
+	}{
+		{
+			name: "Not compressed",
+			bundle: func(testing.TB) []byte {
+				return []byte(`{"format":"aucs-cache-bundle","version":1}`)
+			},
+		},
+		{
+			name: "Missing header",
+			bundle: func(tb testing.TB) []byte {
+				return gzipBundle(tb, "")
+			},
+		},
+		{
+			name: "Unknown format",
+			bundle: func(tb testing.TB) []byte {
+				return gzipBundle(tb, `{"format":"badger-backup","version":1}`)
+			},
+			wantErr: db.ErrUnknownBundleFormat,
+		},
+		{
+			name: "Unsupported version",
+			bundle: func(tb testing.TB) []byte {
+				return gzipBundle(tb, `{"format":"aucs-cache-bundle","version":2}`)
+			},
+			wantErr: db.ErrUnsupportedBundleVersion,
+		},
+		{
+			name: "Malformed entry",
+			bundle: func(tb testing.TB) []byte {
+				return gzipBundle(tb, `{"format":"aucs-cache-bundle","version":1}`, `{"key":`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := inMemoryBadgerStore(t)
+			_, err := db.ImportBundle(testx.Context(t), target, bytes.NewReader(tt.bundle(t)), db.ImportOptions{})
+			if !assert.Error(t, err) {
+				return
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+
+			assert.Empty(t, allEntries(t, target))
+		})
+	}
+}
+
+// bundleEntries returns entries sorted by key as they're returned when iterating a store.
+func bundleEntries(now time.Time) []ports.KeyValueEntry {
+	return []ports.KeyValueEntry{
+		{Key: []byte("npm//is-even-ai"), Value: []byte(`{"LatestVersion":"1.0.5"}`), StoredAt: now.Add(-1 * time.Hour), ExpiresAt: now.Add(5 * time.Hour)},
+		{Key: []byte("pypi//requests"), Value: []byte(`{"LatestVersion":"2.32.3"}`), StoredAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(5 * time.Hour)},
+	}
+}
+
+func putEntries(t *testing.T, kv ports.KeyValueStore, entries ...ports.KeyValueEntry) bool {
+	t.Helper()
+
+	for _, e := range entries {
+		if !assert.NoError(t, kv.PutEntry(testx.Context(t), e)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func allEntries(t *testing.T, kv ports.KeyValueIterator) (entries []ports.KeyValueEntry) {
+	t.Helper()
+
+	err := kv.Iterate(testx.Context(t), nil, func(entry ports.KeyValueEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return entries
+}
+
+// gzipBundle compresses the given JSON documents like a bundle.
+func gzipBundle(tb testing.TB, documents ...string) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+	for _, doc := range documents {
+		if _, err := gzipWriter.Write([]byte(doc + "\n")); err != nil {
+			tb.Fatalf("failed to write bundle: %v", err)
+		}
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		tb.Fatalf("failed to close bundle: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func inMemoryBadgerStore(tb testing.TB) *db.BadgerKVStore {
+	tb.Helper()
+
+	badgerDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		tb.Fatalf("failed to open in-memory badger: %v", err)
+	}
+
+	store := &db.BadgerKVStore{DB: badgerDB, TTL: time.Hour}
+	tb.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	return store
+}
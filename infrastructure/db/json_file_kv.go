@@ -0,0 +1,126 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+var _ ports.KeyValueStore = (*JSONFileKVStore)(nil)
+
+// NewJSONFileKVStore loads all entries of the given JSON file into memory.
+// A missing file is treated like an empty store.
+func NewJSONFileKVStore(filePath string, ttl time.Duration) (*JSONFileKVStore, error) {
+	store := &JSONFileKVStore{
+		MemoryKVStore: NewMemoryKVStore(ttl),
+		FilePath:      filePath,
+	}
+
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return store, nil
+		}
+
+		return nil, err
+	}
+
+	var entries []jsonFileEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	now := time.Now()
+	for _, e := range entries {
+		entry := ports.KeyValueEntry{
+			Key:       e.Key,
+			Value:     e.Value,
+			StoredAt:  e.StoredAt,
+			ExpiresAt: e.ExpiresAt,
+		}
+
+		if !isExpired(entry, now) {
+			store.entries[string(entry.Key)] = entry
+		}
+	}
+
+	return store, nil
+}
+
+// JSONFileKVStore keeps all entries in memory and persists them as single JSON document on Close.
+// Concurrent processes using the same file don't see each others changes and the last one closing wins.
+type JSONFileKVStore struct {
+	*MemoryKVStore
+	FilePath string
+}
+
+type jsonFileEntry struct {
+	Key       []byte    `json:"key"`
+	Value     []byte    `json:"value"`
+	StoredAt  time.Time `json:"stored_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Close implements ports.KeyValueStore.
+func (j *JSONFileKVStore) Close() (err error) {
+	j.lock.RLock()
+
+	now := time.Now()
+	entries := make([]jsonFileEntry, 0, len(j.entries))
+	for _, e := range j.entries {
+		if isExpired(e, now) {
+			continue
+		}
+
+		entries = append(entries, jsonFileEntry{
+			Key:       e.Key,
+			Value:     e.Value,
+			StoredAt:  e.StoredAt,
+			ExpiresAt: e.ExpiresAt,
+		})
+	}
+
+	j.lock.RUnlock()
+
+	slices.SortFunc(entries, func(a, b jsonFileEntry) int {
+		return bytes.Compare(a.Key, b.Key)
+	})
+
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.FilePath), 0o700); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(j.FilePath), filepath.Base(j.FilePath)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if _, err := tmpFile.Write(raw); err != nil {
+		return errors.Join(err, tmpFile.Close())
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), j.FilePath)
+}
@@ -0,0 +1,75 @@
+package db_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/internal/kvtest"
+)
+
+func TestBadgerKVStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	kvtest.RunConformanceSuite(t, func(t *testing.T) ports.KeyValueStore {
+		store, err := db.NewBadgerKVStore(t.TempDir(), time.Hour)
+		if err != nil {
+			t.Fatalf("failed to open badger store: %v", err)
+		}
+
+		return store
+	})
+}
+
+func TestMemoryKVStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	kvtest.RunConformanceSuite(t, func(*testing.T) ports.KeyValueStore {
+		return db.NewMemoryKVStore(time.Hour)
+	})
+}
+
+func TestJSONFileKVStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	kvtest.RunConformanceSuite(t, func(t *testing.T) ports.KeyValueStore {
+		store, err := db.NewJSONFileKVStore(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+		if err != nil {
+			t.Fatalf("failed to open JSON file store: %v", err)
+		}
+
+		return store
+	})
+}
+
+func TestSQLiteKVStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	kvtest.RunConformanceSuite(t, func(t *testing.T) ports.KeyValueStore {
+		store, err := db.NewSQLiteKVStore(filepath.Join(t.TempDir(), "cache.sqlite"), time.Hour)
+		if err != nil {
+			t.Fatalf("failed to open SQLite store: %v", err)
+		}
+
+		return store
+	})
+}
+
+func TestRESPKVStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	kvtest.RunConformanceSuite(t, func(t *testing.T) ports.KeyValueStore {
+		server := miniredis.RunT(t)
+
+		store, err := db.NewRESPKVStore("redis://"+server.Addr()+"/0", time.Hour)
+		if err != nil {
+			t.Fatalf("failed to connect RESP store: %v", err)
+		}
+
+		return store
+	})
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+var _ ports.KeyValueStore = (*MemoryKVStore)(nil)
+
+func NewMemoryKVStore(ttl time.Duration) *MemoryKVStore {
+	return &MemoryKVStore{
+		TTL:     ttl,
+		entries: make(map[string]ports.KeyValueEntry),
+	}
+}
+
+// MemoryKVStore keeps all entries in memory - everything is lost when the process exits.
+type MemoryKVStore struct {
+	TTL time.Duration
+
+	lock    sync.RWMutex
+	entries map[string]ports.KeyValueEntry
+}
+
+// Get implements ports.KeyValueStore.
+func (m *MemoryKVStore) Get(ctx context.Context, key []byte) ([]byte, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	entry, ok := m.entries[string(key)]
+	if !ok || isExpired(entry, time.Now()) {
+		return nil, ports.ErrNoKVEntryForKey
+	}
+
+	return bytes.Clone(entry.Value), nil
+}
+
+// Iterate implements ports.KeyValueStore.
+func (m *MemoryKVStore) Iterate(ctx context.Context, prefix []byte, fn func(entry ports.KeyValueEntry) error) error {
+	m.lock.RLock()
+
+	var (
+		now     = time.Now()
+		matches = make([]ports.KeyValueEntry, 0, len(m.entries))
+	)
+
+	for key, entry := range m.entries {
+		if strings.HasPrefix(key, string(prefix)) && !isExpired(entry, now) {
+			matches = append(matches, cloneEntry(entry))
+		}
+	}
+
+	m.lock.RUnlock()
+
+	slices.SortFunc(matches, func(a, b ports.KeyValueEntry) int {
+		return bytes.Compare(a.Key, b.Key)
+	})
+
+	for _, entry := range matches {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Put implements ports.KeyValueStore.
+func (m *MemoryKVStore) Put(ctx context.Context, key []byte, value []byte) error {
+	now := time.Now()
+
+	return m.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       key,
+		Value:     value,
+		StoredAt:  now,
+		ExpiresAt: expiryFor(now, m.TTL),
+	})
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (m *MemoryKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) error {
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = time.Now()
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.entries[string(entry.Key)] = cloneEntry(entry)
+
+	return nil
+}
+
+// Delete implements ports.KeyValueStore.
+func (m *MemoryKVStore) Delete(ctx context.Context, key []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.entries, string(key))
+
+	return nil
+}
+
+// Close implements ports.KeyValueStore.
+func (m *MemoryKVStore) Close() error {
+	return nil
+}
+
+func isExpired(entry ports.KeyValueEntry, now time.Time) bool {
+	return !entry.ExpiresAt.IsZero() && !entry.ExpiresAt.After(now)
+}
+
+func expiryFor(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
+}
+
+func cloneEntry(entry ports.KeyValueEntry) ports.KeyValueEntry {
+	entry.Key = bytes.Clone(entry.Key)
+	entry.Value = bytes.Clone(entry.Value)
+
+	return entry
+}
@@ -0,0 +1,454 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const (
+	defaultRESPPort      = "6379"
+	defaultRESPKeyPrefix = "aucs:"
+	respScanCount        = "500"
+)
+
+var (
+	ErrUnsupportedRESPScheme = errors.New("unsupported RESP URL scheme")
+	ErrUnexpectedRESPReply   = errors.New("unexpected RESP reply")
+)
+
+var _ ports.KeyValueStore = (*RESPKVStore)(nil)
+
+// NewRESPKVStore connects to a Redis compatible server speaking the RESP protocol.
+// The URL has the form redis[s]://[[user]:password@]host[:port][/db][?prefix=aucs:]
+// - all keys are prefixed with the given prefix to allow sharing the server with other applications.
+func NewRESPKVStore(rawURL string, ttl time.Duration) (*RESPKVStore, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RESP URL: %w", err)
+	}
+
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), defaultRESPPort)
+	}
+
+	var (
+		netDialer = &net.Dialer{Timeout: 10 * time.Second}
+		dialer    interface {
+			DialContext(ctx context.Context, network, address string) (net.Conn, error)
+		}
+	)
+
+	switch parsed.Scheme {
+	case "redis":
+		dialer = netDialer
+	case "rediss":
+		dialer = &tls.Dialer{
+			NetDialer: netDialer,
+			Config: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				ServerName: parsed.Hostname(),
+			},
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRESPScheme, parsed.Scheme)
+	}
+
+	store := &RESPKVStore{
+		TTL:       ttl,
+		KeyPrefix: defaultRESPKeyPrefix,
+		url:       parsed,
+		dial: func(ctx context.Context) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", host)
+		},
+	}
+
+	if parsed.Query().Has("prefix") {
+		store.KeyPrefix = parsed.Query().Get("prefix")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// connect eagerly to fail fast on wrong addresses or credentials
+	if store.conn, err = store.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// RESPKVStore is a minimal RESP2 client sufficient to use a Redis compatible server as shared cache.
+// Commands are sent sequentially over a single connection.
+// The connection is dropped after I/O or protocol errors and re-established by the next command
+// because it's unknown how much of the reply is still pending.
+type RESPKVStore struct {
+	TTL       time.Duration
+	KeyPrefix string
+
+	url  *url.URL
+	dial func(ctx context.Context) (net.Conn, error)
+
+	lock sync.Mutex
+	// conn is nil if the previous connection broke
+	conn *respConn
+}
+
+// Get implements ports.KeyValueStore.
+func (r *RESPKVStore) Get(ctx context.Context, key []byte) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.prefixed(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return nil, ports.ErrNoKVEntryForKey
+	}
+
+	raw, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnexpectedRESPReply, reply)
+	}
+
+	_, value := decodeTimestampedValue(raw)
+
+	return value, nil
+}
+
+// Iterate implements ports.KeyValueStore.
+func (r *RESPKVStore) Iterate(ctx context.Context, prefix []byte, fn func(entry ports.KeyValueEntry) error) error {
+	keys, err := r.scan(ctx, r.prefixed(prefix))
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(keys, bytes.Compare)
+
+	for _, key := range keys {
+		raw, err := r.do(ctx, "GET", key)
+		if err != nil {
+			return err
+		}
+
+		// key expired or was deleted in the meantime
+		value, ok := raw.([]byte)
+		if !ok {
+			continue
+		}
+
+		ttl, err := r.do(ctx, "PTTL", key)
+		if err != nil {
+			return err
+		}
+
+		entry := ports.KeyValueEntry{
+			Key: bytes.TrimPrefix(key, []byte(r.KeyPrefix)),
+		}
+
+		entry.StoredAt, entry.Value = decodeTimestampedValue(value)
+
+		if remaining, ok := ttl.(int64); ok && remaining > 0 {
+			entry.ExpiresAt = time.Now().Add(time.Duration(remaining) * time.Millisecond)
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Put implements ports.KeyValueStore.
+func (r *RESPKVStore) Put(ctx context.Context, key []byte, value []byte) error {
+	now := time.Now()
+
+	return r.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       key,
+		Value:     value,
+		StoredAt:  now,
+		ExpiresAt: expiryFor(now, r.TTL),
+	})
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (r *RESPKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) error {
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = time.Now()
+	}
+
+	if isExpired(entry, time.Now()) {
+		return r.Delete(ctx, entry.Key)
+	}
+
+	args := []any{"SET", r.prefixed(entry.Key), encodeTimestampedValue(entry.StoredAt, entry.Value)}
+	if !entry.ExpiresAt.IsZero() {
+		args = append(args, "PXAT", strconv.FormatInt(entry.ExpiresAt.UnixMilli(), 10))
+	}
+
+	_, err := r.do(ctx, args...)
+
+	return err
+}
+
+// Delete implements ports.KeyValueStore.
+func (r *RESPKVStore) Delete(ctx context.Context, key []byte) error {
+	_, err := r.do(ctx, "DEL", r.prefixed(key))
+	return err
+}
+
+// Close implements ports.KeyValueStore.
+func (r *RESPKVStore) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn = nil
+
+	return err
+}
+
+func (r *RESPKVStore) connect(ctx context.Context) (*respConn, error) {
+	rawConn, err := r.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RESP server: %w", err)
+	}
+
+	conn := &respConn{Conn: rawConn, reader: bufio.NewReader(rawConn)}
+
+	if password, ok := r.url.User.Password(); ok {
+		args := []any{"AUTH", password}
+		if username := r.url.User.Username(); username != "" {
+			args = []any{"AUTH", username, password}
+		}
+
+		if _, err := conn.do(ctx, args...); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to authenticate: %w", err), conn.Close())
+		}
+	}
+
+	if database := strings.Trim(r.url.Path, "/"); database != "" {
+		if _, err := conn.do(ctx, "SELECT", database); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to select database %s: %w", database, err), conn.Close())
+		}
+	}
+
+	return conn, nil
+}
+
+func (r *RESPKVStore) scan(ctx context.Context, prefix []byte) ([][]byte, error) {
+	var (
+		keys    [][]byte
+		cursor  = "0"
+		pattern = append(escapeRESPGlob(prefix), '*')
+	)
+
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", respScanCount)
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("%w: malformed SCAN reply", ErrUnexpectedRESPReply)
+		}
+
+		nextCursor, ok := page[0].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed SCAN cursor", ErrUnexpectedRESPReply)
+		}
+
+		pageKeys, _ := page[1].([]any)
+		for _, k := range pageKeys {
+			if key, ok := k.([]byte); ok {
+				keys = append(keys, key)
+			}
+		}
+
+		if cursor = string(nextCursor); cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+func (r *RESPKVStore) prefixed(key []byte) []byte {
+	return append([]byte(r.KeyPrefix), key...)
+}
+
+// do sends a single command over the shared connection and reads its reply.
+// Arguments have to be either strings or byte slices.
+func (r *RESPKVStore) do(ctx context.Context, args ...any) (any, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.conn == nil {
+		conn, err := r.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		r.conn = conn
+	}
+
+	reply, err := r.conn.do(ctx, args...)
+	if err != nil {
+		// error replies are complete - everything else might leave unread bytes behind
+		// which would be read as reply of the next command
+		var re respError
+		if !errors.As(err, &re) || ctx.Err() != nil {
+			err = errors.Join(err, r.conn.Close())
+			r.conn = nil
+		}
+	}
+
+	return reply, err
+}
+
+// respConn is a single connection including the reader buffering its replies.
+type respConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *respConn) do(ctx context.Context, args ...any) (reply any, err error) {
+	// clear deadlines of previous commands - the context decides when to give up
+	if err := c.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	// unblock pending reads and writes if the context is canceled or its deadline is exceeded
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.SetDeadline(time.Now())
+	})
+
+	defer func() {
+		if !stop() {
+			reply, err = nil, errors.Join(ctx.Err(), err)
+		}
+	}()
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "*%d\r\n", len(args))
+
+	for _, arg := range args {
+		var raw []byte
+		switch a := arg.(type) {
+		case string:
+			raw = []byte(a)
+		case []byte:
+			raw = a
+		default:
+			return nil, fmt.Errorf("unsupported RESP argument type %T", arg)
+		}
+
+		_, _ = fmt.Fprintf(&buf, "$%d\r\n", len(raw))
+		buf.Write(raw)
+		buf.WriteString("\r\n")
+	}
+
+	if _, err := c.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return readRESPReply(c.reader)
+}
+
+// respError is an error reply sent by the server.
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+func readRESPReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = bytes.TrimSuffix(line, []byte("\r\n"))
+	if len(line) == 0 {
+		return nil, fmt.Errorf("%w: empty line", ErrUnexpectedRESPReply)
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return nil, respError(line[1:])
+	case ':':
+		return strconv.ParseInt(string(line[1:]), 10, 64)
+	case '$':
+		length, err := strconv.Atoi(string(line[1:]))
+		if err != nil || length < 0 {
+			return nil, err
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+
+		return data[:length], nil
+	case '*':
+		length, err := strconv.Atoi(string(line[1:]))
+		if err != nil || length < 0 {
+			return nil, err
+		}
+
+		elements := make([]any, 0, length)
+		for range length {
+			element, err := readRESPReply(reader)
+			if err != nil {
+				// keep reading the remaining elements of the array to not leave them behind
+				var re respError
+				if !errors.As(err, &re) {
+					return nil, err
+				}
+
+				element = re
+			}
+			elements = append(elements, element)
+		}
+
+		return elements, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrUnexpectedRESPReply, line[0])
+	}
+}
+
+func escapeRESPGlob(raw []byte) []byte {
+	escaped := make([]byte, 0, len(raw))
+	for _, b := range raw {
+		switch b {
+		case '*', '?', '[', ']', '\\':
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, b)
+	}
+
+	return escaped
+}
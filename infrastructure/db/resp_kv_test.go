@@ -0,0 +1,172 @@
+package db_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestRESPKVStore_ReplyInterrupted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		context func(t *testing.T) (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "Deadline exceeded",
+			context: func(t *testing.T) (context.Context, context.CancelFunc) {
+				return context.WithTimeout(testx.Context(t), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "Context canceled",
+			context: func(t *testing.T) (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(testx.Context(t))
+				time.AfterFunc(50*time.Millisecond, cancel)
+
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newStallingRESPServer(t)
+
+			store, err := db.NewRESPKVStore("redis://"+server.Addr().String(), time.Hour)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			t.Cleanup(func() {
+				_ = store.Close()
+			})
+
+			ctx, cancel := tt.context(t)
+			defer cancel()
+
+			_, err = store.Get(ctx, []byte("npm//slow"))
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			// the rest of the interrupted reply must not be read as reply of the next command
+			got, err := store.Get(testx.Context(t), []byte("npm//fast"))
+			if assert.NoError(t, err) {
+				assert.Equal(t, []byte("1.0.5"), got)
+			}
+
+			assert.EqualValues(t, 2, server.connections.Load())
+		})
+	}
+}
+
+// stallingRESPServer answers GET commands - replies to keys ending with "slow" are stalled halfway.
+type stallingRESPServer struct {
+	net.Listener
+	connections atomic.Int32
+}
+
+func newStallingRESPServer(t *testing.T) *stallingRESPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := &stallingRESPServer{Listener: listener}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			server.connections.Add(1)
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *stallingRESPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		key := args[len(args)-1]
+
+		// zero timestamp header followed by the value
+		value := "\x00\x00\x00\x00\x00\x00\x00\x001.0.5"
+		if strings.HasSuffix(key, "slow") {
+			value = "\x00\x00\x00\x00\x00\x00\x00\x001.0.4"
+		}
+
+		reply := fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+
+		if strings.HasSuffix(key, "slow") {
+			_, _ = io.WriteString(conn, reply[:len(reply)/2])
+			time.Sleep(200 * time.Millisecond)
+			reply = reply[len(reply)/2:]
+		}
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+
+	return args, nil
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS kv_entries (
+	key        BLOB PRIMARY KEY,
+	value      BLOB NOT NULL,
+	stored_at  INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL DEFAULT 0
+) WITHOUT ROWID;
+`
+
+var _ ports.KeyValueStore = (*SQLiteKVStore)(nil)
+
+// NewSQLiteKVStore opens - or creates - a single file SQLite database.
+// The database is opened in WAL mode to allow concurrent readers and writers from multiple processes.
+func NewSQLiteKVStore(filePath string, ttl time.Duration) (*SQLiteKVStore, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return nil, err
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)", filePath)
+
+	sqlDB, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := sqlDB.Exec(sqliteSchema); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to initialize schema: %w", err), sqlDB.Close())
+	}
+
+	if _, err := sqlDB.Exec(`DELETE FROM kv_entries WHERE expires_at > 0 AND expires_at <= ?`, time.Now().UnixNano()); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to prune expired entries: %w", err), sqlDB.Close())
+	}
+
+	return &SQLiteKVStore{DB: sqlDB, TTL: ttl}, nil
+}
+
+type SQLiteKVStore struct {
+	TTL time.Duration
+	DB  *sql.DB
+}
+
+// Get implements ports.KeyValueStore.
+func (s *SQLiteKVStore) Get(ctx context.Context, key []byte) ([]byte, error) {
+	var value []byte
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`SELECT value FROM kv_entries WHERE key = ? AND (expires_at = 0 OR expires_at > ?)`,
+		key, time.Now().UnixNano(),
+	).Scan(&value)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ports.ErrNoKVEntryForKey
+	}
+
+	return value, err
+}
+
+// Iterate implements ports.KeyValueStore.
+func (s *SQLiteKVStore) Iterate(ctx context.Context, prefix []byte, fn func(entry ports.KeyValueEntry) error) (err error) {
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT key, value, stored_at, expires_at FROM kv_entries
+		WHERE (? = 0 OR substr(key, 1, ?) = ?) AND (expires_at = 0 OR expires_at > ?)
+		ORDER BY key`,
+		len(prefix), len(prefix), prefix, time.Now().UnixNano(),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var (
+			entry               ports.KeyValueEntry
+			storedAt, expiresAt int64
+		)
+
+		if err := rows.Scan(&entry.Key, &entry.Value, &storedAt, &expiresAt); err != nil {
+			return err
+		}
+
+		entry.StoredAt = time.Unix(0, storedAt)
+		if expiresAt > 0 {
+			entry.ExpiresAt = time.Unix(0, expiresAt)
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Put implements ports.KeyValueStore.
+func (s *SQLiteKVStore) Put(ctx context.Context, key []byte, value []byte) error {
+	now := time.Now()
+
+	return s.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       key,
+		Value:     value,
+		StoredAt:  now,
+		ExpiresAt: expiryFor(now, s.TTL),
+	})
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (s *SQLiteKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) error {
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = time.Now()
+	}
+
+	var expiresAt int64
+	if !entry.ExpiresAt.IsZero() {
+		expiresAt = entry.ExpiresAt.UnixNano()
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		`INSERT INTO kv_entries (key, value, stored_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, stored_at = excluded.stored_at, expires_at = excluded.expires_at`,
+		entry.Key, entry.Value, entry.StoredAt.UnixNano(), expiresAt,
+	)
+
+	return err
+}
+
+// Delete implements ports.KeyValueStore.
+func (s *SQLiteKVStore) Delete(ctx context.Context, key []byte) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM kv_entries WHERE key = ?`, key)
+	return err
+}
+
+// Close implements ports.KeyValueStore.
+func (s *SQLiteKVStore) Close() error {
+	return s.DB.Close()
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const timestampHeaderLength = 8
+
+// encodeTimestampedValue prefixes value with the time it was stored at
+// for stores that can't keep additional metadata next to a value.
+func encodeTimestampedValue(storedAt time.Time, value []byte) []byte {
+	encoded := make([]byte, timestampHeaderLength, timestampHeaderLength+len(value))
+	binary.BigEndian.PutUint64(encoded, uint64(storedAt.UnixNano()))
+
+	return append(encoded, value...)
+}
+
+func decodeTimestampedValue(raw []byte) (storedAt time.Time, value []byte) {
+	if len(raw) < timestampHeaderLength {
+		return time.Time{}, raw
+	}
+
+	return time.Unix(0, int64(binary.BigEndian.Uint64(raw[:timestampHeaderLength]))), raw[timestampHeaderLength:]
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+var _ ports.KeyValueStore = (*BadgerWriteBackKVStore)(nil)
+
+// NewBadgerWriteBackKVStore creates a store buffering all entries and deletions in memory
+// and merging them into the Badger database at dbPath on Close.
+// It's used as fallback if another process holds the lock of the Badger database.
+// The store starts empty because the locked database can't be read - all lookups are cache misses.
+func NewBadgerWriteBackKVStore(dbPath string, ttl, lockTimeout time.Duration) *BadgerWriteBackKVStore {
+	return &BadgerWriteBackKVStore{
+		MemoryKVStore: NewMemoryKVStore(ttl),
+		DBPath:        dbPath,
+		LockTimeout:   lockTimeout,
+		deleted:       make(map[string]time.Time),
+	}
+}
+
+type BadgerWriteBackKVStore struct {
+	*MemoryKVStore
+	DBPath      string
+	LockTimeout time.Duration
+
+	// deleted keeps tombstones of deleted keys with the time they were deleted at
+	// to replay the deletions on write-back - guarded by the lock of the embedded store
+	deleted map[string]time.Time
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (w *BadgerWriteBackKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) error {
+	if err := w.MemoryKVStore.PutEntry(ctx, entry); err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.deleted, string(entry.Key))
+
+	return nil
+}
+
+// Put implements ports.KeyValueStore.
+func (w *BadgerWriteBackKVStore) Put(ctx context.Context, key []byte, value []byte) error {
+	now := time.Now()
+
+	return w.PutEntry(ctx, ports.KeyValueEntry{
+		Key:       key,
+		Value:     value,
+		StoredAt:  now,
+		ExpiresAt: expiryFor(now, w.TTL),
+	})
+}
+
+// Delete implements ports.KeyValueStore.
+func (w *BadgerWriteBackKVStore) Delete(ctx context.Context, key []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.entries, string(key))
+	w.deleted[string(key)] = time.Now()
+
+	return nil
+}
+
+// Close implements ports.KeyValueStore.
+func (w *BadgerWriteBackKVStore) Close() (err error) {
+	w.lock.RLock()
+	entries, deleted := len(w.entries), maps.Clone(w.deleted)
+	w.lock.RUnlock()
+
+	if entries == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	target, err := WaitForBadgerKVStore(w.DBPath, w.TTL, w.LockTimeout)
+	if err != nil {
+		if errors.Is(err, ErrDatabaseLocked) {
+			slog.Warn("Database still locked - discarding buffered cache entries",
+				slog.String("path", w.DBPath),
+				slog.Int("entries", entries),
+				slog.Int("deletions", len(deleted)),
+			)
+
+			return nil
+		}
+
+		return err
+	}
+
+	defer func() {
+		err = errors.Join(err, target.Close())
+	}()
+
+	ctx := context.Background()
+
+	// entries written by other processes in the meantime win if they're newer than the buffered changes
+	existing := make(map[string]time.Time)
+	err = target.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		existing[string(entry.Key)] = entry.StoredAt
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, deletedAt := range deleted {
+		if storedAt, ok := existing[key]; !ok || storedAt.After(deletedAt) {
+			continue
+		}
+
+		if err := target.Delete(ctx, []byte(key)); err != nil {
+			return fmt.Errorf("failed to write back deletion of cache entry %s: %w", key, err)
+		}
+	}
+
+	return w.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		if storedAt, ok := existing[string(entry.Key)]; ok && storedAt.After(entry.StoredAt) {
+			return nil
+		}
+
+		if err := target.PutEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to write back cache entry %s: %w", entry.Key, err)
+		}
+
+		return nil
+	})
+}
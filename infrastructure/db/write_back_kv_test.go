@@ -0,0 +1,113 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestBadgerWriteBackKVStore_MergesAfterLockRelease(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = testx.Context(t)
+		dbPath = t.TempDir()
+	)
+
+	holder, err := db.NewBadgerKVStore(dbPath, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = db.WaitForBadgerKVStore(dbPath, time.Hour, 300*time.Millisecond)
+	if !assert.ErrorIs(t, err, db.ErrDatabaseLocked) {
+		return
+	}
+
+	writeBack := db.NewBadgerWriteBackKVStore(dbPath, time.Hour, 10*time.Second)
+	if !assert.NoError(t, writeBack.Put(ctx, []byte("npm//is-even-ai"), []byte("1.0.5"))) {
+		return
+	}
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		_ = holder.Close()
+	})
+
+	if !assert.NoError(t, writeBack.Close()) {
+		return
+	}
+
+	store, err := db.NewBadgerKVStore(dbPath, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	got, err := store.Get(ctx, []byte("npm//is-even-ai"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("1.0.5"), got)
+	}
+}
+
+func TestBadgerWriteBackKVStore_ReplaysDeletions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = testx.Context(t)
+		dbPath = t.TempDir()
+	)
+
+	holder, err := db.NewBadgerKVStore(dbPath, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, key := range []string{"npm//is-even-ai", "npm//is-odd", "npm//is-number"} {
+		if !assert.NoError(t, holder.Put(ctx, []byte(key), []byte("1.0.0"))) {
+			return
+		}
+	}
+
+	writeBack := db.NewBadgerWriteBackKVStore(dbPath, time.Hour, 10*time.Second)
+	assert.NoError(t, writeBack.Delete(ctx, []byte("npm//is-even-ai")))
+	assert.NoError(t, writeBack.Delete(ctx, []byte("npm//is-odd")))
+	assert.NoError(t, writeBack.Put(ctx, []byte("npm//is-odd"), []byte("1.0.1")))
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		_ = holder.Close()
+	})
+
+	if !assert.NoError(t, writeBack.Close()) {
+		return
+	}
+
+	store, err := db.NewBadgerKVStore(dbPath, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	_, err = store.Get(ctx, []byte("npm//is-even-ai"))
+	assert.ErrorIs(t, err, ports.ErrNoKVEntryForKey)
+
+	got, err := store.Get(ctx, []byte("npm//is-odd"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("1.0.1"), got)
+	}
+
+	got, err = store.Get(ctx, []byte("npm//is-number"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("1.0.0"), got)
+	}
+}
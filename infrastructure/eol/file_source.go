@@ -0,0 +1,177 @@
+package eol
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const identifierTypePurl = "purl"
+
+var _ ports.EndOfLifeSource = (*FileSource)(nil)
+
+// NewFileSource loads products from JSON files modeled after https://endoflife.date
+// - every file contains a list of products with their identifiers and release cycles.
+func NewFileSource(paths ...string) (*FileSource, error) {
+	source := &FileSource{
+		productsByPackage: make(map[packageKey]*product),
+	}
+
+	for _, p := range paths {
+		if err := source.load(p); err != nil {
+			return nil, fmt.Errorf("failed to load end-of-life data %s: %w", p, err)
+		}
+	}
+
+	return source, nil
+}
+
+// FileSource matches package versions against the release cycles of products loaded from local files.
+// Products are matched by their package URL identifiers, cycles by the longest version prefix.
+type FileSource struct {
+	productsByPackage map[packageKey]*product
+}
+
+type packageKey struct {
+	packageType string
+	namespace   string
+	name        string
+}
+
+// EndOfLifeFor implements ports.EndOfLifeSource.
+func (s *FileSource) EndOfLifeFor(_ context.Context, packageUrl string) (*ports.EndOfLifeCycle, error) {
+	purl, err := packageurl.FromString(packageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ports.ErrInvalidPackageURL, err)
+	}
+
+	p, ok := s.productsByPackage[packageKeyFor(purl)]
+	if !ok || purl.Version == "" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoEndOfLifeData, packageUrl)
+	}
+
+	c, ok := p.cycleFor(purl.Version)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrNoEndOfLifeData, packageUrl)
+	}
+
+	return &ports.EndOfLifeCycle{
+		Product: p.Name,
+		Cycle:   c.Cycle,
+		EOL:     c.EOL.EOL,
+		EOLDate: c.EOL.Date,
+		Latest:  c.Latest,
+	}, nil
+}
+
+func (s *FileSource) load(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var products []*product
+	if err := json.Unmarshal(raw, &products); err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		for _, id := range p.Identifiers {
+			if id.Type != identifierTypePurl {
+				continue
+			}
+
+			purl, err := packageurl.FromString(id.ID)
+			if err != nil {
+				return fmt.Errorf("product %s: %w: %w", p.Name, ports.ErrInvalidPackageURL, err)
+			}
+
+			s.productsByPackage[packageKeyFor(purl)] = p
+		}
+	}
+
+	return nil
+}
+
+func packageKeyFor(purl packageurl.PackageURL) packageKey {
+	return packageKey{
+		packageType: purl.Type,
+		namespace:   strings.ToLower(purl.Namespace),
+		name:        strings.ToLower(purl.Name),
+	}
+}
+
+type product struct {
+	Name        string `json:"name"`
+	Identifiers []struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"identifiers"`
+	Cycles []cycle `json:"cycles"`
+}
+
+// cycleFor returns the cycle with the longest version prefix matching the given version e.g. 3.12 for 3.12.4.
+func (p product) cycleFor(version string) (cycle, bool) {
+	var (
+		match cycle
+		found bool
+	)
+
+	version = strings.TrimPrefix(version, "v")
+
+	for _, c := range p.Cycles {
+		if version != c.Cycle && !strings.HasPrefix(version, c.Cycle+".") {
+			continue
+		}
+
+		if !found || len(c.Cycle) > len(match.Cycle) {
+			match, found = c, true
+		}
+	}
+
+	return match, found
+}
+
+type cycle struct {
+	Cycle  string   `json:"cycle"`
+	EOL    eolValue `json:"eol"`
+	Latest string   `json:"latest"`
+}
+
+// eolValue is either a date or a boolean if the date is unknown.
+type eolValue struct {
+	EOL  bool
+	Date time.Time
+}
+
+func (v *eolValue) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &v.EOL); err == nil {
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("eol has to be a date or a boolean: %w", err)
+	}
+
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return fmt.Errorf("eol has to be a date or a boolean: %w", err)
+	}
+
+	v.Date = date
+
+	return nil
+}
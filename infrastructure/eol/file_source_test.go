@@ -0,0 +1,104 @@
+package eol_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/eol"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestFileSource_EndOfLifeFor(t *testing.T) {
+	t.Parallel()
+
+	source, err := eol.NewFileSource("testdata/products.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name       string
+		packageUrl string
+		want       *ports.EndOfLifeCycle
+		wantErr    error
+	}{
+		{
+			name:       "Cycle with EOL date",
+			packageUrl: "pkg:generic/node@18.20.4",
+			want: &ports.EndOfLifeCycle{
+				Product: "nodejs",
+				Cycle:   "18",
+				EOLDate: time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+				Latest:  "18.20.5",
+			},
+		},
+		{
+			name:       "Cycle without EOL date",
+			packageUrl: "pkg:generic/node@v0.10.1",
+			want: &ports.EndOfLifeCycle{
+				Product: "nodejs",
+				Cycle:   "0.10",
+				EOL:     true,
+				Latest:  "0.10.48",
+			},
+		},
+		{
+			name:       "Case-insensitive package name",
+			packageUrl: "pkg:pypi/Django@4.2.1",
+			want: &ports.EndOfLifeCycle{
+				Product: "django",
+				Cycle:   "4.2",
+				EOLDate: time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC),
+				Latest:  "4.2.16",
+			},
+		},
+		{
+			name:       "Unknown cycle",
+			packageUrl: "pkg:pypi/django@4.1.0",
+			wantErr:    ports.ErrNoEndOfLifeData,
+		},
+		{
+			name:       "Prefix is not a cycle",
+			packageUrl: "pkg:generic/node@180.1.0",
+			wantErr:    ports.ErrNoEndOfLifeData,
+		},
+		{
+			name:       "Unknown package",
+			packageUrl: "pkg:npm/is-even-ai@1.0.1",
+			wantErr:    ports.ErrNoEndOfLifeData,
+		},
+		{
+			name:       "Invalid package URL",
+			packageUrl: "node@18",
+			wantErr:    ports.ErrInvalidPackageURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := source.EndOfLifeFor(testx.Context(t), tt.packageUrl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEndOfLifeCycle_IsEOL(t *testing.T) {
+	t.Parallel()
+
+	cycle := ports.EndOfLifeCycle{EOLDate: time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC)}
+
+	assert.False(t, cycle.IsEOL(time.Date(2025, time.April, 29, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, cycle.IsEOL(time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, ports.EndOfLifeCycle{}.IsEOL(time.Now()))
+}
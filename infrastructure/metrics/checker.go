@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+// InstrumentChecker records the duration and outcome of every lookup of checker.
+// Conditional checkers stay conditional.
+func (m *Metrics) InstrumentChecker(checker ports.UpdateChecker) ports.UpdateChecker {
+	instrumented := instrumentedChecker{UpdateChecker: checker, metrics: m}
+
+	if conditional, ok := checker.(ports.ConditionalUpdateChecker); ok {
+		return instrumentedConditionalChecker{instrumentedChecker: instrumented, conditional: conditional}
+	}
+
+	return instrumented
+}
+
+var _ ports.UpdateChecker = (*instrumentedChecker)(nil)
+
+type instrumentedChecker struct {
+	ports.UpdateChecker
+	metrics *Metrics
+}
+
+// LatestVersionFor implements ports.UpdateChecker.
+func (i instrumentedChecker) LatestVersionFor(ctx context.Context, packageUrl packageurl.PackageURL) (info *ports.PackageInfo, err error) {
+	defer func(start time.Time) {
+		i.metrics.observeChecker(i.SupportedPackageType(), start, err)
+	}(time.Now())
+
+	return i.UpdateChecker.LatestVersionFor(ctx, packageUrl)
+}
+
+var _ ports.ConditionalUpdateChecker = (*instrumentedConditionalChecker)(nil)
+
+type instrumentedConditionalChecker struct {
+	instrumentedChecker
+	conditional ports.ConditionalUpdateChecker
+}
+
+// LatestVersionIfModified implements ports.ConditionalUpdateChecker.
+func (i instrumentedConditionalChecker) LatestVersionIfModified(
+	ctx context.Context,
+	packageUrl packageurl.PackageURL,
+	validators ports.CacheValidators,
+) (info *ports.PackageInfo, received ports.CacheValidators, err error) {
+	defer func(start time.Time) {
+		i.metrics.observeChecker(i.SupportedPackageType(), start, err)
+	}(time.Now())
+
+	return i.conditional.LatestVersionIfModified(ctx, packageUrl, validators)
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const namespace = "aucs"
+
+var _ ports.LookupObserver = (*Metrics)(nil)
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "lookups_total",
+			Help:      "Number of package lookups by ecosystem and outcome",
+		}, []string{"ecosystem", "outcome"}),
+		cacheResults: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_results_total",
+			Help:      "Number of cache hits, misses and stale entries served by ecosystem",
+		}, []string{"ecosystem", "result"}),
+		checkerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "checker_duration_seconds",
+			Help:      "Duration of registry lookups by ecosystem and outcome",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"ecosystem", "outcome"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_client_requests_total",
+			Help:      "Number of outgoing HTTP requests by client, method and status code",
+		}, []string{"client", "method", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_client_request_duration_seconds",
+			Help:      "Latency of outgoing HTTP requests by client",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"client", "method", "code"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.lookups,
+		m.cacheResults,
+		m.checkerDuration,
+		m.httpRequests,
+		m.httpDuration,
+		circuitBreakerCollector{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, "circuit_breaker", "open"),
+				"Whether the circuit breaker of a HTTP client is open (1) or closed (0)",
+				[]string{"command"},
+				nil,
+			),
+		},
+	)
+
+	return m
+}
+
+// Metrics collects Prometheus metrics of lookups, checkers and HTTP clients.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	lookups         *prometheus.CounterVec
+	cacheResults    *prometheus.CounterVec
+	checkerDuration *prometheus.HistogramVec
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+}
+
+// ObserveLookup implements ports.LookupObserver.
+func (m *Metrics) ObserveLookup(packageType string, cacheStatus ports.CacheStatus, err error) {
+	outcome := ports.LookupOutcomeFor(err)
+
+	m.lookups.WithLabelValues(packageType, string(outcome)).Inc()
+
+	// lookups for unsupported or invalid package URLs never hit the cache
+	if outcome != ports.LookupOutcomeUnsupported && outcome != ports.LookupOutcomeInvalid {
+		m.cacheResults.WithLabelValues(packageType, string(cacheStatus)).Inc()
+	}
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// WriteTextfile writes all metrics to path to be picked up by the node exporter's textfile collector.
+func (m *Metrics) WriteTextfile(path string) error {
+	return prometheus.WriteToTextfile(path, m.Registry)
+}
+
+// InstrumentTransport records count and latency of all requests sent through next.
+func (m *Metrics) InstrumentTransport(client string, next http.RoundTripper) http.RoundTripper {
+	labels := prometheus.Labels{"client": client}
+
+	return promhttp.InstrumentRoundTripperCounter(
+		m.httpRequests.MustCurryWith(labels),
+		promhttp.InstrumentRoundTripperDuration(m.httpDuration.MustCurryWith(labels), next),
+	)
+}
+
+func (m *Metrics) observeChecker(ecosystem string, start time.Time, err error) {
+	m.checkerDuration.
+		WithLabelValues(ecosystem, string(ports.LookupOutcomeFor(err))).
+		Observe(time.Since(start).Seconds())
+}
+
+var _ prometheus.Collector = (*circuitBreakerCollector)(nil)
+
+// circuitBreakerCollector reports the state of all configured hystrix commands when scraped.
+type circuitBreakerCollector struct {
+	desc *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector.
+func (c circuitBreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c circuitBreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for command := range hystrix.GetCircuitSettings() {
+		circuit, _, err := hystrix.GetCircuit(command)
+		if err != nil {
+			continue
+		}
+
+		var open float64
+		if circuit.IsOpen() {
+			open = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, open, command)
+	}
+}
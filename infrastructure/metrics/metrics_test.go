@@ -0,0 +1,76 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/metrics"
+)
+
+func TestMetrics_ObserveLookup(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	m.ObserveLookup("npm", ports.CacheStatusHit, nil)
+	m.ObserveLookup("npm", ports.CacheStatusMiss, fmt.Errorf("%w: is-odd-ai", ports.ErrNoMatchingPackageFound))
+	m.ObserveLookup("golang", ports.CacheStatusMiss, ports.ErrNoCheckerForPackageType)
+	m.ObserveLookup("", ports.CacheStatusMiss, ports.ErrInvalidPackageURL)
+
+	expected := `
+# HELP aucs_cache_results_total Number of cache hits, misses and stale entries served by ecosystem
+# TYPE aucs_cache_results_total counter
+aucs_cache_results_total{ecosystem="npm",result="hit"} 1
+aucs_cache_results_total{ecosystem="npm",result="miss"} 1
+# HELP aucs_lookups_total Number of package lookups by ecosystem and outcome
+# TYPE aucs_lookups_total counter
+aucs_lookups_total{ecosystem="",outcome="invalid"} 1
+aucs_lookups_total{ecosystem="golang",outcome="unsupported"} 1
+aucs_lookups_total{ecosystem="npm",outcome="found"} 1
+aucs_lookups_total{ecosystem="npm",outcome="not_found"} 1
+`
+
+	err := testutil.GatherAndCompare(m.Registry, strings.NewReader(expected), "aucs_cache_results_total", "aucs_lookups_total")
+	assert.NoError(t, err)
+}
+
+func TestMetrics_InstrumentChecker_KeepsConditionalCheckers(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	_, ok := m.InstrumentChecker(conditionalChecker{}).(ports.ConditionalUpdateChecker)
+	assert.True(t, ok)
+
+	_, ok = m.InstrumentChecker(plainChecker{}).(ports.ConditionalUpdateChecker)
+	assert.False(t, ok)
+}
+
+type plainChecker struct{}
+
+func (plainChecker) LatestVersionFor(context.Context, packageurl.PackageURL) (*ports.PackageInfo, error) {
+	return &ports.PackageInfo{}, nil
+}
+
+func (plainChecker) SupportedPackageType() string {
+	return "npm"
+}
+
+type conditionalChecker struct {
+	plainChecker
+}
+
+func (conditionalChecker) LatestVersionIfModified(
+	context.Context,
+	packageurl.PackageURL,
+	ports.CacheValidators,
+) (*ports.PackageInfo, ports.CacheValidators, error) {
+	return &ports.PackageInfo{}, ports.CacheValidators{}, nil
+}
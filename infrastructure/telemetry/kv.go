@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const instrumentationName = "github.com/prskr/aucs/infrastructure/telemetry"
+
+var _ ports.KeyValueStore = (*tracingKVStore)(nil)
+
+// TraceKVStore wraps the given store and records a span for every operation.
+func TraceKVStore(kv ports.KeyValueStore) ports.KeyValueStore {
+	return &tracingKVStore{
+		next:   kv,
+		tracer: otel.Tracer(instrumentationName),
+	}
+}
+
+type tracingKVStore struct {
+	next   ports.KeyValueStore
+	tracer trace.Tracer
+}
+
+// Get implements ports.KeyValueStore.
+func (t *tracingKVStore) Get(ctx context.Context, key []byte) (value []byte, err error) {
+	ctx, span := t.start(ctx, "Get", key)
+	defer func() {
+		span.SetAttributes(attribute.Bool("aucs.kv.hit", err == nil))
+		endSpan(span, err, ports.ErrNoKVEntryForKey)
+	}()
+
+	return t.next.Get(ctx, key)
+}
+
+// Iterate implements ports.KeyValueStore.
+func (t *tracingKVStore) Iterate(ctx context.Context, prefix []byte, fn func(entry ports.KeyValueEntry) error) (err error) {
+	ctx, span := t.start(ctx, "Iterate", prefix)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return t.next.Iterate(ctx, prefix, fn)
+}
+
+// Put implements ports.KeyValueStore.
+func (t *tracingKVStore) Put(ctx context.Context, key []byte, value []byte) (err error) {
+	ctx, span := t.start(ctx, "Put", key)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return t.next.Put(ctx, key, value)
+}
+
+// PutEntry implements ports.KeyValueStore.
+func (t *tracingKVStore) PutEntry(ctx context.Context, entry ports.KeyValueEntry) (err error) {
+	ctx, span := t.start(ctx, "PutEntry", entry.Key)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return t.next.PutEntry(ctx, entry)
+}
+
+// Delete implements ports.KeyValueStore.
+func (t *tracingKVStore) Delete(ctx context.Context, key []byte) (err error) {
+	ctx, span := t.start(ctx, "Delete", key)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return t.next.Delete(ctx, key)
+}
+
+// Close implements ports.KeyValueStore.
+func (t *tracingKVStore) Close() error {
+	return t.next.Close()
+}
+
+func (t *tracingKVStore) start(ctx context.Context, operation string, key []byte) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "KV "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.operation.name", operation),
+			attribute.String("aucs.kv.key", string(key)),
+		),
+	)
+}
+
+// endSpan records the error on the span unless it is one of the expected errors.
+func endSpan(span trace.Span, err error, expected ...error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	for _, e := range expected {
+		if errors.Is(err, e) {
+			return
+		}
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
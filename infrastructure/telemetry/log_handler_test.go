@@ -0,0 +1,45 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/prskr/aucs/infrastructure/telemetry"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestNewLogHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(testx.Context(t), "test")
+	logger.InfoContext(ctx, "with span")
+	span.End()
+
+	logger.InfoContext(testx.Context(t), "without span")
+
+	var records []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record map[string]any
+		if !assert.NoError(t, dec.Decode(&record)) {
+			return
+		}
+		records = append(records, record)
+	}
+
+	if !assert.Len(t, records, 2) {
+		return
+	}
+
+	assert.Equal(t, span.SpanContext().TraceID().String(), records[0]["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), records[0]["span_id"])
+	assert.NotContains(t, records[1], "trace_id")
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const defaultServiceName = "aucs"
+
+var (
+	ErrUnsupportedTracesExporter = errors.New("unsupported traces exporter")
+	ErrUnsupportedOTLPProtocol   = errors.New("unsupported OTLP protocol")
+)
+
+// ShutdownFunc flushes pending spans and releases the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// SetupTracing configures the global tracer provider and propagator from the standard OTEL_* environment variables.
+// Spans are only exported if an OTLP endpoint is configured or OTEL_TRACES_EXPORTER=otlp is set explicitly,
+// otherwise the no-op provider stays in place.
+// Only the http/protobuf OTLP protocol is supported.
+func SetupTracing(ctx context.Context) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if enabled, err := tracingEnabled(); err != nil || !enabled {
+		return func(context.Context) error { return nil }, err
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(
+		ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to detect resource: %w", err), exporter.Shutdown(ctx))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func tracingEnabled() (bool, error) {
+	if disabled, _ := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); disabled {
+		return false, nil
+	}
+
+	switch protocol := firstEnv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"); protocol {
+	case "", "http/protobuf":
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedOTLPProtocol, protocol)
+	}
+
+	switch exporter := strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER")); exporter {
+	case "none":
+		return false, nil
+	case "otlp":
+		return true, nil
+	case "":
+		return firstEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT") != "", nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedTracesExporter, exporter)
+	}
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,113 @@
+package vulnerability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
+)
+
+// CacheKeyPrefix separates cached vulnerability reports from cached lookups sharing the same store
+// - package URL types can't contain '!' hence the prefix can't be mistaken for an ecosystem.
+// The prefix is followed by the lookup cache key of the package version.
+const CacheKeyPrefix = "!vulnerabilities/"
+
+var ErrNoVulnerabilityCacheKey = errors.New("not a vulnerability report cache key")
+
+var _ ports.VulnerabilitySource = (*CachedSource)(nil)
+
+func NewCachedSource(kv ports.KeyValueStore, source ports.VulnerabilitySource) CachedSource {
+	return CachedSource{KV: kv, Source: source}
+}
+
+// CachedSource stores the reports of the underlying source per package version.
+// Only package versions without cached report are passed to the source - all of them in a single call.
+type CachedSource struct {
+	KV     ports.KeyValueStore
+	Source ports.VulnerabilitySource
+}
+
+// VulnerabilitiesFor implements ports.VulnerabilitySource.
+func (c CachedSource) VulnerabilitiesFor(ctx context.Context, packageUrls []string) (map[string]ports.VulnerabilityReport, error) {
+	var (
+		reports = make(map[string]ports.VulnerabilityReport, len(packageUrls))
+		missing = make([]string, 0, len(packageUrls))
+	)
+
+	for _, purl := range packageUrls {
+		raw, err := c.KV.Get(ctx, cacheKeyFor(purl))
+		if err != nil {
+			if errors.Is(err, ports.ErrNoKVEntryForKey) {
+				missing = append(missing, purl)
+				continue
+			}
+
+			return nil, err
+		}
+
+		var report ports.VulnerabilityReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			missing = append(missing, purl)
+			continue
+		}
+
+		reports[purl] = report
+	}
+
+	if len(missing) == 0 {
+		return reports, nil
+	}
+
+	fetched, err := c.Source.VulnerabilitiesFor(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for purl, report := range fetched {
+		raw, err := json.Marshal(report)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.KV.Put(ctx, cacheKeyFor(purl), raw); err != nil {
+			return nil, err
+		}
+
+		reports[purl] = report
+	}
+
+	return reports, nil
+}
+
+// IsCacheKey tells whether the key belongs to a cached vulnerability report.
+func IsCacheKey(key []byte) bool {
+	return bytes.HasPrefix(key, []byte(CacheKeyPrefix))
+}
+
+// PackageURLFromCacheKey restores the package URL of a cached vulnerability report.
+func PackageURLFromCacheKey(key []byte) (packageurl.PackageURL, error) {
+	lookupKey, ok := bytes.CutPrefix(key, []byte(CacheKeyPrefix))
+	if !ok {
+		return packageurl.PackageURL{}, fmt.Errorf("%w: %s", ErrNoVulnerabilityCacheKey, key)
+	}
+
+	return checker.PackageURLFromCacheKey(lookupKey)
+}
+
+// cacheKeyFor derives the key from the lookup cache key to allow invalidating both with the same package URL patterns.
+// Unparseable package URLs are kept as they are, they're still unique.
+func cacheKeyFor(purl string) []byte {
+	key := []byte(CacheKeyPrefix)
+
+	if parsed, err := packageurl.FromString(purl); err == nil {
+		return append(key, checker.CacheKeyFor(parsed)...)
+	}
+
+	return append(key, purl...)
+}
@@ -0,0 +1,118 @@
+package vulnerability_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/infrastructure/checker"
+	"github.com/prskr/aucs/infrastructure/db"
+	"github.com/prskr/aucs/infrastructure/vulnerability"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestCachedSource_VulnerabilitiesFor(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	source := &fakeSource{reports: map[string]ports.VulnerabilityReport{
+		"pkg:npm/lodash@4.17.15": {
+			Vulnerabilities:           []ports.Vulnerability{{ID: "GHSA-p6mc-m468-83gw", Source: "OSV"}},
+			FirstNonVulnerableVersion: "4.17.19",
+		},
+		"pkg:npm/is-even-ai@1.0.1": {},
+	}}
+
+	cached := vulnerability.NewCachedSource(db.NewMemoryKVStore(time.Hour), source)
+
+	got, err := cached.VulnerabilitiesFor(ctx, []string{"pkg:npm/lodash@4.17.15"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "4.17.19", got["pkg:npm/lodash@4.17.15"].FirstNonVulnerableVersion)
+
+	got, err = cached.VulnerabilitiesFor(ctx, []string{"pkg:npm/lodash@4.17.15", "pkg:npm/is-even-ai@1.0.1"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "GHSA-p6mc-m468-83gw", got["pkg:npm/lodash@4.17.15"].Vulnerabilities[0].ID)
+	assert.Equal(t, [][]string{
+		{"pkg:npm/lodash@4.17.15"},
+		{"pkg:npm/is-even-ai@1.0.1"},
+	}, source.received())
+}
+
+func TestCachedSource_CacheKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := testx.Context(t)
+	kv := db.NewMemoryKVStore(time.Hour)
+	source := &fakeSource{reports: map[string]ports.VulnerabilityReport{
+		"pkg:deb/debian/curl@7.88.1-10?distro=debian-12": {},
+	}}
+
+	_, err := vulnerability.NewCachedSource(kv, source).VulnerabilitiesFor(ctx, []string{"pkg:deb/debian/curl@7.88.1-10?distro=debian-12"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var keys [][]byte
+	err = kv.Iterate(ctx, nil, func(entry ports.KeyValueEntry) error {
+		keys = append(keys, entry.Key)
+		return nil
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, keys, 1) {
+		return
+	}
+
+	// reports must not be mistaken for cached lookups
+	assert.True(t, vulnerability.IsCacheKey(keys[0]))
+
+	_, err = checker.PackageURLFromCacheKey(keys[0])
+	assert.ErrorIs(t, err, checker.ErrMalformedCacheKey)
+
+	purl, err := vulnerability.PackageURLFromCacheKey(keys[0])
+	if assert.NoError(t, err) {
+		assert.Equal(t, "pkg:deb/debian/curl@7.88.1-10?distro=debian-12", purl.ToString())
+	}
+
+	_, err = vulnerability.PackageURLFromCacheKey([]byte("deb/debian/curl@7.88.1-10"))
+	assert.ErrorIs(t, err, vulnerability.ErrNoVulnerabilityCacheKey)
+}
+
+type fakeSource struct {
+	reports map[string]ports.VulnerabilityReport
+
+	lock  sync.Mutex
+	calls [][]string
+}
+
+func (f *fakeSource) VulnerabilitiesFor(_ context.Context, packageUrls []string) (map[string]ports.VulnerabilityReport, error) {
+	f.lock.Lock()
+	f.calls = append(f.calls, slices.Clone(packageUrls))
+	f.lock.Unlock()
+
+	reports := make(map[string]ports.VulnerabilityReport, len(packageUrls))
+	for _, purl := range packageUrls {
+		if report, ok := f.reports[purl]; ok {
+			reports[purl] = report
+		}
+	}
+
+	return reports, nil
+}
+
+func (f *fakeSource) received() [][]string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.calls
+}
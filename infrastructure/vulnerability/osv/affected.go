@@ -0,0 +1,199 @@
+package osv
+
+import (
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/prskr/aucs/infrastructure/checker/apk"
+	"github.com/prskr/aucs/infrastructure/checker/cran"
+	"github.com/prskr/aucs/infrastructure/checker/deb"
+	"github.com/prskr/aucs/infrastructure/checker/rpm"
+)
+
+const (
+	rangeTypeSemver    = "SEMVER"
+	rangeTypeEcosystem = "ECOSYSTEM"
+	rangeTypeGit       = "GIT"
+	introducedAnything = "0"
+)
+
+// versionOrder compares versions of an ecosystem - valid rejects versions the ordering isn't defined for.
+type versionOrder struct {
+	valid   func(version string) bool
+	compare func(a, b string) int
+}
+
+var (
+	semverOrder = versionOrder{
+		valid: func(version string) bool {
+			_, err := semver.NewVersion(version)
+			return err == nil
+		},
+		compare: func(a, b string) int {
+			return semver.MustParse(a).Compare(semver.MustParse(b))
+		},
+	}
+
+	// ecosystemOrders are the orderings of ECOSYSTEM ranges - ranges of other ecosystems e.g. PyPI (PEP 440),
+	// Maven or NuGet are not evaluated, only their explicitly listed versions are matched.
+	ecosystemOrders = map[string]versionOrder{
+		"crates.io":    semverOrder,
+		"Go":           semverOrder,
+		"Hex":          semverOrder,
+		"npm":          semverOrder,
+		"Pub":          semverOrder,
+		"Debian":       totalOrder(deb.CompareVersions),
+		"Ubuntu":       totalOrder(deb.CompareVersions),
+		"Alpine":       totalOrder(apk.CompareVersions),
+		"AlmaLinux":    totalOrder(rpm.CompareVersions),
+		"Red Hat":      totalOrder(rpm.CompareVersions),
+		"Rocky Linux":  totalOrder(rpm.CompareVersions),
+		"openSUSE":     totalOrder(rpm.CompareVersions),
+		"SUSE":         totalOrder(rpm.CompareVersions),
+		"CRAN":         totalOrder(cran.CompareVersions),
+		"Bioconductor": totalOrder(cran.CompareVersions),
+	}
+)
+
+// totalOrder wraps comparisons defined for all non-empty versions.
+func totalOrder(compare func(a, b string) int) versionOrder {
+	return versionOrder{
+		valid:   func(version string) bool { return version != "" },
+		compare: compare,
+	}
+}
+
+// orderFor returns the ordering the range has to be evaluated with - GIT ranges and ranges of unknown ecosystems
+// can't be evaluated.
+func (a affected) orderFor(r affectedRange) (versionOrder, bool) {
+	switch r.Type {
+	case rangeTypeSemver:
+		return semverOrder, true
+	case rangeTypeEcosystem:
+		ecosystem, _, _ := cutEcosystem(a.Package.Ecosystem)
+		order, ok := ecosystemOrders[ecosystem]
+
+		return order, ok
+	default:
+		return versionOrder{}, false
+	}
+}
+
+// isAffected evaluates the affected versions and ranges of the given entries for a single version.
+// Versions that can't be ordered are only matched against explicitly listed versions.
+func isAffected(version string, entries []affected) bool {
+	for _, a := range entries {
+		if slices.Contains(a.Versions, version) {
+			return true
+		}
+
+		for _, r := range a.Ranges {
+			if order, ok := a.orderFor(r); ok && order.valid(version) && r.contains(version, order) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// firstNonVulnerableVersion returns the lowest fixed version greater than the current version
+// that isn't affected by any of the vulnerabilities.
+func firstNonVulnerableVersion(current string, vulns [][]affected) string {
+	type candidate struct {
+		version string
+		order   versionOrder
+	}
+
+	var candidates []candidate
+
+	for _, entries := range vulns {
+		for _, a := range entries {
+			for _, r := range a.Ranges {
+				order, ok := a.orderFor(r)
+				if !ok || !order.valid(current) {
+					continue
+				}
+
+				for _, e := range r.Events {
+					if e.Fixed != "" && order.valid(e.Fixed) && order.compare(e.Fixed, current) > 0 {
+						candidates = append(candidates, candidate{version: e.Fixed, order: order})
+					}
+				}
+			}
+		}
+	}
+
+	// all ranges of a package share the ordering of its ecosystem or are SEMVER ranges
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.order.compare(a.version, b.version)
+	})
+
+candidates:
+	for _, c := range candidates {
+		for _, entries := range vulns {
+			if isAffected(c.version, entries) {
+				continue candidates
+			}
+		}
+
+		return c.version
+	}
+
+	return ""
+}
+
+// contains walks the events ordered by version - every event at or below the version toggles whether it is affected.
+func (r affectedRange) contains(version string, order versionOrder) bool {
+	type orderedEvent struct {
+		event
+		version string
+	}
+
+	events := make([]orderedEvent, 0, len(r.Events))
+
+	for _, e := range r.Events {
+		raw := e.Introduced + e.Fixed + e.LastAffected + e.Limit
+		if e.Introduced == introducedAnything {
+			events = append(events, orderedEvent{event: e})
+			continue
+		}
+
+		if order.valid(raw) {
+			events = append(events, orderedEvent{event: e, version: raw})
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b orderedEvent) int {
+		switch {
+		case a.version == "" && b.version == "":
+			return 0
+		case a.version == "":
+			return -1
+		case b.version == "":
+			return 1
+		default:
+			return order.compare(a.version, b.version)
+		}
+	})
+
+	affected := false
+
+	for _, e := range events {
+		if e.version != "" && order.compare(e.version, version) > 0 {
+			break
+		}
+
+		switch {
+		case e.Introduced != "":
+			affected = true
+		case e.Fixed != "", e.Limit != "":
+			affected = false
+		case e.LastAffected != "" && order.compare(e.version, version) < 0:
+			affected = false
+		}
+	}
+
+	return affected
+}
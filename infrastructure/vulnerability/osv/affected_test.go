@@ -0,0 +1,157 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isAffected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		entries []affected
+		want    bool
+	}{
+		{
+			name:    "Introduced and fixed - in range",
+			version: "4.17.15",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "3.7.0"}, event{Fixed: "4.17.19"})}}},
+			want:    true,
+		},
+		{
+			name:    "Introduced and fixed - fixed version",
+			version: "4.17.19",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "3.7.0"}, event{Fixed: "4.17.19"})}}},
+			want:    false,
+		},
+		{
+			name:    "Introduced and fixed - before introduction",
+			version: "3.6.0",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "3.7.0"}, event{Fixed: "4.17.19"})}}},
+			want:    false,
+		},
+		{
+			name:    "Introduced anything",
+			version: "0.0.1",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "0"}, event{Fixed: "1.0.0"})}}},
+			want:    true,
+		},
+		{
+			name:    "Last affected - equal",
+			version: "1.2.0",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "1.0.0"}, event{LastAffected: "1.2.0"})}}},
+			want:    true,
+		},
+		{
+			name:    "Last affected - greater",
+			version: "1.2.1",
+			entries: []affected{{Ranges: []affectedRange{semverRange(event{Introduced: "1.0.0"}, event{LastAffected: "1.2.0"})}}},
+			want:    false,
+		},
+		{
+			name:    "Multiple introductions - unordered events",
+			version: "2.1.0",
+			entries: []affected{{Ranges: []affectedRange{semverRange(
+				event{Fixed: "2.2.0"},
+				event{Introduced: "1.0.0"},
+				event{Introduced: "2.0.0"},
+				event{Fixed: "1.5.0"},
+			)}}},
+			want: true,
+		},
+		{
+			name:    "Ecosystem range - Debian ordering",
+			version: "2.36-9+deb12u4",
+			entries: []affected{debianAffected(ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.36-9+deb12u7"}))},
+			want:    true,
+		},
+		{
+			name:    "Ecosystem range - Debian epoch",
+			version: "1:2.0-1",
+			entries: []affected{debianAffected(ecosystemRange(event{Introduced: "0"}, event{Fixed: "1:1.5-1"}))},
+			want:    false,
+		},
+		{
+			name:    "Ecosystem range - unknown ordering is not evaluated",
+			version: "1.5",
+			entries: []affected{pypiAffected(nil, ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.0"}))},
+			want:    false,
+		},
+		{
+			name:    "Ecosystem range - unknown ordering with explicit versions",
+			version: "1.0.post1",
+			entries: []affected{pypiAffected([]string{"1.0", "1.0.post1"}, ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.0"}))},
+			want:    true,
+		},
+		{
+			name:    "Semver range of ecosystem without ordering",
+			version: "1.5.0",
+			entries: []affected{pypiAffected(nil, semverRange(event{Introduced: "0"}, event{Fixed: "2.0.0"}))},
+			want:    true,
+		},
+		{
+			name:    "Explicit versions - unparseable",
+			version: "2.0.0rc1",
+			entries: []affected{{Versions: []string{"2.0.0rc1"}}},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isAffected(tt.version, tt.entries))
+		})
+	}
+}
+
+func Test_firstNonVulnerableVersion(t *testing.T) {
+	t.Parallel()
+
+	vulns := [][]affected{
+		{{Ranges: []affectedRange{semverRange(event{Introduced: "0"}, event{Fixed: "1.2.0"})}}},
+		// the first fix is affected by the second vulnerability
+		{{Ranges: []affectedRange{semverRange(event{Introduced: "1.1.0"}, event{Fixed: "1.2.3"})}}},
+	}
+
+	assert.Equal(t, "1.2.3", firstNonVulnerableVersion("1.1.5", vulns))
+	assert.Equal(t, "", firstNonVulnerableVersion("not-a-version", vulns))
+
+	debianVulns := [][]affected{
+		{debianAffected(ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.36-9+deb12u4"}))},
+		{debianAffected(ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.36-9+deb12u7"}))},
+	}
+
+	assert.Equal(t, "2.36-9+deb12u7", firstNonVulnerableVersion("2.36-9+deb12u3", debianVulns))
+
+	pypiVulns := [][]affected{
+		{pypiAffected([]string{"2.0rc1"}, ecosystemRange(event{Introduced: "0"}, event{Fixed: "2.0"}))},
+	}
+
+	assert.Equal(t, "", firstNonVulnerableVersion("2.0rc1", pypiVulns))
+}
+
+func debianAffected(ranges ...affectedRange) affected {
+	a := affected{Ranges: ranges}
+	a.Package.Ecosystem = "Debian:12"
+
+	return a
+}
+
+func pypiAffected(versions []string, ranges ...affectedRange) affected {
+	a := affected{Ranges: ranges, Versions: versions}
+	a.Package.Ecosystem = "PyPI"
+
+	return a
+}
+
+func ecosystemRange(events ...event) affectedRange {
+	return affectedRange{Type: "ECOSYSTEM", Events: events}
+}
+
+func semverRange(events ...event) affectedRange {
+	return affectedRange{Type: "SEMVER", Events: events}
+}
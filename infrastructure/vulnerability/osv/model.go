@@ -0,0 +1,204 @@
+package osv
+
+import (
+	"strings"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+// ecosystemsByPackageType maps package URL types to OSV ecosystems.
+var ecosystemsByPackageType = map[string]string{
+	"bioconductor": "Bioconductor",
+	"cargo":        "crates.io",
+	"composer":     "Packagist",
+	"cran":         "CRAN",
+	"gem":          "RubyGems",
+	"golang":       "Go",
+	"hex":          "Hex",
+	"maven":        "Maven",
+	"npm":          "npm",
+	"nuget":        "NuGet",
+	"pub":          "Pub",
+	"pypi":         "PyPI",
+	"swift":        "SwiftURL",
+}
+
+// vulnerability is an entry as defined by the OSV schema - only the fields used by aucs are mapped.
+type vulnerability struct {
+	ID        string     `json:"id"`
+	Aliases   []string   `json:"aliases"`
+	Summary   string     `json:"summary"`
+	Details   string     `json:"details"`
+	Published time.Time  `json:"published"`
+	Modified  time.Time  `json:"modified"`
+	Withdrawn *time.Time `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected   []affected `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+}
+
+type affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+		Purl      string `json:"purl"`
+	} `json:"package"`
+	Ranges   []affectedRange `json:"ranges"`
+	Versions []string        `json:"versions"`
+}
+
+type affectedRange struct {
+	Type   string  `json:"type"`
+	Events []event `json:"events"`
+}
+
+type event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// affectedFor returns all affected entries referring to the package of the given package URL.
+func (v vulnerability) affectedFor(purl packageurl.PackageURL) []affected {
+	var matches []affected
+
+	for _, a := range v.Affected {
+		if a.matches(purl) {
+			matches = append(matches, a)
+		}
+	}
+
+	return matches
+}
+
+func (v vulnerability) toPort(purl packageurl.PackageURL) ports.Vulnerability {
+	result := ports.Vulnerability{
+		ID:        v.ID,
+		Source:    "OSV",
+		URL:       "https://osv.dev/vulnerability/" + v.ID,
+		Aliases:   v.Aliases,
+		Summary:   v.Summary,
+		Details:   v.Details,
+		Published: v.Published,
+		Modified:  v.Modified,
+	}
+
+	for _, s := range v.Severity {
+		result.Severities = append(result.Severities, ports.VulnerabilitySeverity{Type: s.Type, Score: s.Score})
+	}
+
+	for _, r := range v.References {
+		result.References = append(result.References, r.URL)
+	}
+
+	for _, a := range v.affectedFor(purl) {
+		for _, r := range a.Ranges {
+			for _, e := range r.Events {
+				if e.Fixed != "" && r.Type != rangeTypeGit {
+					result.FixedVersions = append(result.FixedVersions, e.Fixed)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func (a affected) matches(purl packageurl.PackageURL) bool {
+	if a.Package.Purl != "" {
+		if candidate, err := packageurl.FromString(a.Package.Purl); err == nil {
+			return candidate.Type == purl.Type &&
+				strings.EqualFold(candidate.Namespace, purl.Namespace) &&
+				normalizeName(purl.Type, candidate.Name) == normalizeName(purl.Type, purl.Name)
+		}
+	}
+
+	ecosystem, name, ok := packageKeyFor(purl)
+	if !ok {
+		return false
+	}
+
+	affectedEcosystem, _, _ := cutEcosystem(a.Package.Ecosystem)
+
+	return affectedEcosystem == ecosystem && normalizeName(purl.Type, a.Package.Name) == name
+}
+
+// cutEcosystem splits the release suffix of ecosystems like Debian:12 off.
+func cutEcosystem(ecosystem string) (name, release string, found bool) {
+	return strings.Cut(ecosystem, ":")
+}
+
+// packageTypeFor is the reverse of the package URL type to OSV ecosystem mapping.
+func packageTypeFor(ecosystem string) (string, bool) {
+	for packageType, e := range ecosystemsByPackageType {
+		if e == ecosystem {
+			return packageType, true
+		}
+	}
+
+	return "", false
+}
+
+// packageKeyFor maps the package URL to the OSV ecosystem and package name.
+func packageKeyFor(purl packageurl.PackageURL) (ecosystem, name string, ok bool) {
+	ecosystem, ok = ecosystemsByPackageType[purl.Type]
+	if !ok {
+		return "", "", false
+	}
+
+	switch {
+	case purl.Namespace == "":
+		name = purl.Name
+	case purl.Type == "maven":
+		name = purl.Namespace + ":" + purl.Name
+	default:
+		name = purl.Namespace + "/" + purl.Name
+	}
+
+	return ecosystem, normalizeName(purl.Type, name), true
+}
+
+// normalizeName applies the package name normalization of ecosystems with case-insensitive names.
+func normalizeName(packageType, name string) string {
+	switch packageType {
+	case "pypi":
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	case "nuget", "composer":
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
+
+// reportFor converts the vulnerabilities affecting the given package version to a report.
+func reportFor(purl packageurl.PackageURL, vulns []vulnerability) ports.VulnerabilityReport {
+	var (
+		report  ports.VulnerabilityReport
+		entries = make([][]affected, 0, len(vulns))
+	)
+
+	for _, v := range vulns {
+		if v.Withdrawn != nil {
+			continue
+		}
+
+		report.Vulnerabilities = append(report.Vulnerabilities, v.toPort(purl))
+		entries = append(entries, v.affectedFor(purl))
+	}
+
+	if len(report.Vulnerabilities) > 0 {
+		report.FirstNonVulnerableVersion = firstNonVulnerableVersion(purl.Version, entries)
+	}
+
+	return report
+}
@@ -0,0 +1,136 @@
+package osv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+var _ ports.VulnerabilitySource = (*OfflineSource)(nil)
+
+// NewOfflineSource loads OSV dumps e.g. https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip
+// - every dump is a zip archive containing one JSON document per vulnerability.
+func NewOfflineSource(dumpPaths ...string) (*OfflineSource, error) {
+	source := &OfflineSource{
+		vulnsByPackage: make(map[packageKey][]vulnerability),
+	}
+
+	for _, p := range dumpPaths {
+		if err := source.load(p); err != nil {
+			return nil, fmt.Errorf("failed to load OSV dump %s: %w", p, err)
+		}
+	}
+
+	return source, nil
+}
+
+// OfflineSource matches package versions against vulnerabilities loaded from OSV dumps.
+type OfflineSource struct {
+	vulnsByPackage map[packageKey][]vulnerability
+}
+
+type packageKey struct {
+	ecosystem string
+	name      string
+}
+
+// VulnerabilitiesFor implements ports.VulnerabilitySource.
+func (s *OfflineSource) VulnerabilitiesFor(ctx context.Context, packageUrls []string) (map[string]ports.VulnerabilityReport, error) {
+	reports := make(map[string]ports.VulnerabilityReport, len(packageUrls))
+
+	for _, raw := range packageUrls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		purl, err := packageurl.FromString(raw)
+		if err != nil || purl.Version == "" {
+			continue
+		}
+
+		var matches []vulnerability
+
+		if ecosystem, name, ok := packageKeyFor(purl); ok {
+			for _, v := range s.vulnsByPackage[packageKey{ecosystem: ecosystem, name: name}] {
+				if isAffected(purl.Version, v.affectedFor(purl)) {
+					matches = append(matches, v)
+				}
+			}
+		}
+
+		reports[raw] = reportFor(purl, matches)
+	}
+
+	return reports, nil
+}
+
+func (s *OfflineSource) load(dumpPath string) (err error) {
+	archive, err := zip.OpenReader(dumpPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = errors.Join(err, archive.Close())
+	}()
+
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || path.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		vuln, err := readVulnerability(f)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+
+		s.index(vuln)
+	}
+
+	return nil
+}
+
+// index registers the vulnerability once for every distinct package it affects.
+func (s *OfflineSource) index(vuln vulnerability) {
+	seen := make(map[packageKey]bool, len(vuln.Affected))
+
+	for _, a := range vuln.Affected {
+		ecosystem, _, _ := cutEcosystem(a.Package.Ecosystem)
+
+		key := packageKey{ecosystem: ecosystem, name: a.Package.Name}
+		if purl, err := packageurl.FromString(a.Package.Purl); err == nil {
+			if e, name, ok := packageKeyFor(purl); ok {
+				key = packageKey{ecosystem: e, name: name}
+			}
+		} else if packageType, ok := packageTypeFor(ecosystem); ok {
+			key.name = normalizeName(packageType, a.Package.Name)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			s.vulnsByPackage[key] = append(s.vulnsByPackage[key], vuln)
+		}
+	}
+}
+
+func readVulnerability(f *zip.File) (vuln vulnerability, err error) {
+	reader, err := f.Open()
+	if err != nil {
+		return vuln, err
+	}
+
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+
+	err = json.NewDecoder(reader).Decode(&vuln)
+
+	return vuln, err
+}
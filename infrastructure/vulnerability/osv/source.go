@@ -0,0 +1,191 @@
+package osv
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/package-url/packageurl-go"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+const (
+	DefaultBaseURL = "https://api.osv.dev"
+
+	// maxBatchSize is the maximum number of queries the querybatch endpoint accepts
+	maxBatchSize = 1000
+	// maxParallelFetches limits the concurrent requests to fetch vulnerability details
+	maxParallelFetches = 10
+)
+
+var _ ports.VulnerabilitySource = (*Source)(nil)
+
+func NewSource(client *http.Client, baseURL string) Source {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return Source{Client: client, BaseURL: baseURL}
+}
+
+// Source queries the OSV API - matching package versions are determined by the querybatch endpoint,
+// the details of every matching vulnerability are fetched afterwards.
+type Source struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// VulnerabilitiesFor implements ports.VulnerabilitySource.
+func (s Source) VulnerabilitiesFor(ctx context.Context, packageUrls []string) (map[string]ports.VulnerabilityReport, error) {
+	purls := make(map[string]packageurl.PackageURL, len(packageUrls))
+
+	for _, raw := range packageUrls {
+		if purl, err := packageurl.FromString(raw); err == nil && purl.Version != "" {
+			purls[raw] = purl
+		}
+	}
+
+	queried := make([]string, 0, len(purls))
+	for raw := range purls {
+		queried = append(queried, raw)
+	}
+
+	slices.Sort(queried)
+
+	idsByPackageURL := make(map[string][]string, len(queried))
+
+	for batch := range slices.Chunk(queried, maxBatchSize) {
+		if err := s.queryBatch(ctx, batch, idsByPackageURL); err != nil {
+			return nil, err
+		}
+	}
+
+	vulnsByID, err := s.fetchVulnerabilities(ctx, idsByPackageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make(map[string]ports.VulnerabilityReport, len(queried))
+
+	for _, raw := range queried {
+		vulns := make([]vulnerability, 0, len(idsByPackageURL[raw]))
+		for _, id := range idsByPackageURL[raw] {
+			vulns = append(vulns, vulnsByID[id])
+		}
+
+		reports[raw] = reportFor(purls[raw], vulns)
+	}
+
+	return reports, nil
+}
+
+// queryBatch resolves the IDs of all vulnerabilities affecting the given package versions
+// and follows the page tokens of queries with more results.
+func (s Source) queryBatch(ctx context.Context, packageUrls []string, idsByPackageURL map[string][]string) error {
+	pending := make([]batchQuery, 0, len(packageUrls))
+	for _, raw := range packageUrls {
+		pending = append(pending, batchQuery{Package: batchPackage{Purl: raw}})
+	}
+
+	for len(pending) > 0 {
+		var resp batchResponse
+
+		err := requests.
+			URL(s.BaseURL).
+			Path("/v1/querybatch").
+			Client(s.Client).
+			BodyJSON(batchRequest{Queries: pending}).
+			ToJSON(&resp).
+			Fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		next := pending[:0:0]
+
+		for i, result := range resp.Results {
+			if i >= len(pending) {
+				break
+			}
+
+			raw := pending[i].Package.Purl
+			for _, v := range result.Vulns {
+				idsByPackageURL[raw] = append(idsByPackageURL[raw], v.ID)
+			}
+
+			if result.NextPageToken != "" {
+				next = append(next, batchQuery{Package: pending[i].Package, PageToken: result.NextPageToken})
+			}
+		}
+
+		pending = next
+	}
+
+	return nil
+}
+
+func (s Source) fetchVulnerabilities(ctx context.Context, idsByPackageURL map[string][]string) (map[string]vulnerability, error) {
+	var (
+		lock      sync.Mutex
+		vulnsByID = make(map[string]vulnerability)
+	)
+
+	for _, ids := range idsByPackageURL {
+		for _, id := range ids {
+			vulnsByID[id] = vulnerability{}
+		}
+	}
+
+	grp, grpCtx := errgroup.WithContext(ctx)
+	grp.SetLimit(maxParallelFetches)
+
+	for id := range vulnsByID {
+		grp.Go(func() error {
+			var vuln vulnerability
+
+			err := requests.
+				URL(s.BaseURL).
+				Pathf("/v1/vulns/%s", id).
+				Client(s.Client).
+				ToJSON(&vuln).
+				Fetch(grpCtx)
+			if err != nil {
+				return err
+			}
+
+			lock.Lock()
+			vulnsByID[id] = vuln
+			lock.Unlock()
+
+			return nil
+		})
+	}
+
+	return vulnsByID, grp.Wait()
+}
+
+type batchRequest struct {
+	Queries []batchQuery `json:"queries"`
+}
+
+type batchQuery struct {
+	Package   batchPackage `json:"package"`
+	PageToken string       `json:"page_token,omitempty"`
+}
+
+type batchPackage struct {
+	Purl string `json:"purl"`
+}
+
+type batchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+		NextPageToken string `json:"next_page_token"`
+	} `json:"results"`
+}
@@ -0,0 +1,119 @@
+package osv_test
+
+import (
+	"archive/zip"
+	_ "embed"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/infrastructure/vulnerability/osv"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+var (
+	//go:embed testdata/querybatch.json
+	queryBatchResponse []byte
+
+	//go:embed testdata/GHSA-p6mc-m468-83gw.json
+	lodashVulnerability []byte
+)
+
+func TestSource_VulnerabilitiesFor(t *testing.T) {
+	t.Parallel()
+
+	queryBatchRule, err := testx.NewSimpleUrlRule("https://osv.example.com/v1/querybatch", queryBatchResponse)
+	if err != nil {
+		t.Fatalf("failed to create query batch rule: %v", err)
+	}
+
+	vulnRule, err := testx.NewSimpleUrlRule("https://osv.example.com/v1/vulns/GHSA-p6mc-m468-83gw", lodashVulnerability)
+	if err != nil {
+		t.Fatalf("failed to create vulnerability rule: %v", err)
+	}
+
+	source := osv.NewSource(testx.MockHTTPClient(queryBatchRule, vulnRule), "https://osv.example.com")
+
+	reports, err := source.VulnerabilitiesFor(testx.Context(t), []string{
+		"pkg:npm/lodash@4.17.15",
+		"pkg:npm/left-pad@1.3.0",
+		"pkg:npm/is-even-ai",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, reports, 2)
+	assert.Empty(t, reports["pkg:npm/left-pad@1.3.0"].Vulnerabilities)
+
+	lodash := reports["pkg:npm/lodash@4.17.15"]
+	if assert.Len(t, lodash.Vulnerabilities, 1) {
+		assert.Equal(t, "GHSA-p6mc-m468-83gw", lodash.Vulnerabilities[0].ID)
+		assert.Equal(t, []string{"4.17.19"}, lodash.Vulnerabilities[0].FixedVersions)
+	}
+
+	assert.Equal(t, "4.17.19", lodash.FirstNonVulnerableVersion)
+}
+
+func TestOfflineSource_VulnerabilitiesFor(t *testing.T) {
+	t.Parallel()
+
+	dumpPath := filepath.Join(t.TempDir(), "all.zip")
+	writeDump(t, dumpPath, map[string][]byte{"GHSA-p6mc-m468-83gw.json": lodashVulnerability})
+
+	source, err := osv.NewOfflineSource(dumpPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	reports, err := source.VulnerabilitiesFor(testx.Context(t), []string{
+		"pkg:npm/lodash@4.17.15",
+		"pkg:npm/lodash@4.17.21",
+		"pkg:npm/lodash-es@4.17.19",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, reports["pkg:npm/lodash@4.17.15"].Vulnerabilities, 1)
+	assert.Empty(t, reports["pkg:npm/lodash@4.17.21"].Vulnerabilities)
+
+	lodashES := reports["pkg:npm/lodash-es@4.17.19"]
+	if assert.Len(t, lodashES.Vulnerabilities, 1) {
+		assert.Equal(t, []string{"4.17.20"}, lodashES.Vulnerabilities[0].FixedVersions)
+	}
+
+	assert.Equal(t, "4.17.20", lodashES.FirstNonVulnerableVersion)
+}
+
+func writeDump(t *testing.T, path string, files map[string][]byte) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create dump: %v", err)
+	}
+
+	archive := zip.NewWriter(f)
+
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s to dump: %v", name, err)
+		}
+
+		if _, err := w.Write(content); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to finish dump: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close dump: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+package httpx
+
+import "strings"
+
+// BaseURL ensures the base URL ends with a slash - otherwise relative request paths replace its last path segment
+// e.g. for self-hosted registries served below a path prefix.
+func BaseURL(raw string) string {
+	return strings.TrimSuffix(raw, "/") + "/"
+}
@@ -0,0 +1,40 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/carlmjohnson/requests"
+
+	"github.com/prskr/aucs/core/ports"
+)
+
+// FetchConditional sends the request built by rb with the given validators as If-None-Match/If-Modified-Since headers.
+// It returns ports.ErrNotModified if the server answered with 304 Not Modified, otherwise the response is passed to handler.
+// The returned validators are the ones of the received response.
+func FetchConditional(
+	ctx context.Context,
+	rb *requests.Builder,
+	validators ports.CacheValidators,
+	handler requests.ResponseHandler,
+) (received ports.CacheValidators, err error) {
+	err = rb.
+		HeaderOptional("If-None-Match", validators.ETag).
+		HeaderOptional("If-Modified-Since", validators.LastModified).
+		CheckStatus(http.StatusOK, http.StatusNotModified).
+		Handle(func(res *http.Response) error {
+			received = ports.CacheValidators{
+				ETag:         res.Header.Get("ETag"),
+				LastModified: res.Header.Get("Last-Modified"),
+			}
+
+			if res.StatusCode == http.StatusNotModified {
+				return ports.ErrNotModified
+			}
+
+			return handler(res)
+		}).
+		Fetch(ctx)
+
+	return received, err
+}
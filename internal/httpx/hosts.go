@@ -0,0 +1,69 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var ErrHostNotAllowed = errors.New("host not allowed")
+
+// AllowHosts restricts requests to the given hosts - entries are either hosts with optional port or URLs
+// whose host is taken. Hosts without port match all ports.
+func AllowHosts(hostsOrURLs ...string) AllowedHosts {
+	allowed := AllowedHosts{hosts: make(map[string]struct{}, len(hostsOrURLs))}
+
+	for _, entry := range hostsOrURLs {
+		if strings.Contains(entry, "://") {
+			parsed, err := url.Parse(entry)
+			if err != nil {
+				continue
+			}
+
+			entry = parsed.Host
+		}
+
+		if entry = strings.ToLower(strings.TrimSuffix(entry, "/")); entry != "" {
+			allowed.hosts[entry] = struct{}{}
+		}
+	}
+
+	return allowed
+}
+
+// AllowedHosts restricts the hosts of URLs taken from untrusted input e.g. the repository_url qualifier of package
+// URLs submitted to the API server. The zero value allows all hosts.
+type AllowedHosts struct {
+	hosts map[string]struct{}
+}
+
+// Check returns ErrHostNotAllowed if the URL doesn't use HTTP(S) or points to a host that isn't allowed.
+func (a AllowedHosts) Check(rawURL string) error {
+	if a.hosts == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHostNotAllowed, err)
+	}
+
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return fmt.Errorf("%w: unsupported scheme of %s", ErrHostNotAllowed, rawURL)
+	}
+
+	host := strings.ToLower(parsed.Host)
+	if _, ok := a.hosts[host]; ok {
+		return nil
+	}
+
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if _, ok := a.hosts[hostname]; ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrHostNotAllowed, parsed.Host)
+}
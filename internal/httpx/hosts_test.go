@@ -0,0 +1,44 @@
+package httpx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/internal/httpx"
+)
+
+func TestAllowedHosts_Check(t *testing.T) {
+	t.Parallel()
+
+	allowed := httpx.AllowHosts("https://pub.example.com/pub/", "Registry.Example.com", "mirror.example.com:8443", "")
+
+	tests := []struct {
+		name    string
+		allowed httpx.AllowedHosts
+		url     string
+		wantErr bool
+	}{
+		{name: "Host of allowed URL", allowed: allowed, url: "https://pub.example.com/other"},
+		{name: "Hosts are case-insensitive", allowed: allowed, url: "https://registry.example.com"},
+		{name: "Host without port matches all ports", allowed: allowed, url: "http://registry.example.com:8080/"},
+		{name: "Host with port", allowed: allowed, url: "https://mirror.example.com:8443/conda-forge"},
+		{name: "Host with other port", allowed: allowed, url: "https://mirror.example.com/conda-forge", wantErr: true},
+		{name: "Other host", allowed: allowed, url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "Unsupported scheme", allowed: allowed, url: "file://pub.example.com/etc/passwd", wantErr: true},
+		{name: "Zero value allows all hosts", url: "http://localhost:6379"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.allowed.Check(tt.url)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, httpx.ErrHostNotAllowed)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
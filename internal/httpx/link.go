@@ -0,0 +1,16 @@
+package httpx
+
+import "strings"
+
+// NextLink extracts the URL of the next page from a Link header e.g. <https://api.github.com/...&page=2>; rel="next".
+// The URL might be relative to the request URL e.g. for OCI registries.
+func NextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, found := strings.Cut(strings.TrimSpace(link), ";")
+		if found && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,175 @@
+package kvtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/core/ports"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+// Opener creates a new, empty store for a single test - the store is closed by the suite.
+type Opener func(t *testing.T) ports.KeyValueStore
+
+// RunConformanceSuite verifies the behavior all ports.KeyValueStore implementations have to share.
+func RunConformanceSuite(t *testing.T, open Opener) {
+	t.Helper()
+
+	t.Run("Get missing key", func(t *testing.T) {
+		store := openStore(t, open)
+
+		_, err := store.Get(testx.Context(t), []byte("npm//missing"))
+		assert.ErrorIs(t, err, ports.ErrNoKVEntryForKey)
+	})
+
+	t.Run("Put and Get", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		if !assert.NoError(t, store.Put(ctx, []byte("npm//is-even-ai"), []byte("1.0.4"))) {
+			return
+		}
+
+		if !assert.NoError(t, store.Put(ctx, []byte("npm//is-even-ai"), []byte("1.0.5"))) {
+			return
+		}
+
+		got, err := store.Get(ctx, []byte("npm//is-even-ai"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("1.0.5"), got)
+		}
+	})
+
+	t.Run("PutEntry keeps timestamps", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		storedAt := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+		expiresAt := time.Now().Add(1 * time.Hour).Truncate(time.Second)
+
+		err := store.PutEntry(ctx, ports.KeyValueEntry{
+			Key:       []byte("pypi//requests"),
+			Value:     []byte("2.32.3"),
+			StoredAt:  storedAt,
+			ExpiresAt: expiresAt,
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		entries := collect(t, store, nil)
+		if !assert.Len(t, entries, 1) {
+			return
+		}
+
+		assert.Equal(t, []byte("pypi//requests"), entries[0].Key)
+		assert.Equal(t, []byte("2.32.3"), entries[0].Value)
+		assert.True(t, storedAt.Equal(entries[0].StoredAt), "stored at %s, got %s", storedAt, entries[0].StoredAt)
+		assert.WithinDuration(t, expiresAt, entries[0].ExpiresAt, time.Second)
+	})
+
+	t.Run("Expired entries are hidden", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		err := store.PutEntry(ctx, ports.KeyValueEntry{
+			Key:       []byte("npm//expired"),
+			Value:     []byte("1.0.0"),
+			StoredAt:  time.Now().Add(-2 * time.Hour),
+			ExpiresAt: time.Now().Add(-1 * time.Hour),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = store.Get(ctx, []byte("npm//expired"))
+		assert.ErrorIs(t, err, ports.ErrNoKVEntryForKey)
+		assert.Empty(t, collect(t, store, nil))
+	})
+
+	t.Run("Iterate by prefix in key order", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		for _, key := range []string{"pypi//requests", "npm//is-odd", "npm//is-even-ai", "nuget//Serilog"} {
+			if !assert.NoError(t, store.Put(ctx, []byte(key), []byte("value"))) {
+				return
+			}
+		}
+
+		var keys []string
+		for _, e := range collect(t, store, []byte("npm/")) {
+			keys = append(keys, string(e.Key))
+		}
+
+		assert.Equal(t, []string{"npm//is-even-ai", "npm//is-odd"}, keys)
+		assert.Len(t, collect(t, store, nil), 4)
+	})
+
+	t.Run("Iterate stops at first error", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		for _, key := range []string{"npm//a", "npm//b"} {
+			if !assert.NoError(t, store.Put(ctx, []byte(key), []byte("value"))) {
+				return
+			}
+		}
+
+		errStop := errors.New("stop")
+		calls := 0
+		err := store.Iterate(ctx, nil, func(ports.KeyValueEntry) error {
+			calls++
+			return errStop
+		})
+
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		ctx := testx.Context(t)
+		store := openStore(t, open)
+
+		if !assert.NoError(t, store.Put(ctx, []byte("npm//is-even-ai"), []byte("1.0.5"))) {
+			return
+		}
+
+		assert.NoError(t, store.Delete(ctx, []byte("npm//is-even-ai")))
+		assert.NoError(t, store.Delete(ctx, []byte("npm//missing")))
+
+		_, err := store.Get(ctx, []byte("npm//is-even-ai"))
+		assert.ErrorIs(t, err, ports.ErrNoKVEntryForKey)
+	})
+}
+
+func openStore(t *testing.T, open Opener) ports.KeyValueStore {
+	t.Helper()
+
+	store := open(t)
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	})
+
+	return store
+}
+
+func collect(t *testing.T, store ports.KeyValueIterator, prefix []byte) []ports.KeyValueEntry {
+	t.Helper()
+
+	var entries []ports.KeyValueEntry
+	err := store.Iterate(testx.Context(t), prefix, func(entry ports.KeyValueEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to iterate store: %v", err)
+	}
+
+	return entries
+}
@@ -0,0 +1,103 @@
+// Package memo caches expensive to load values e.g. parsed repository indices in memory.
+package memo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTTL limits how long loaded values e.g. parsed repository indices are kept by long-running servers.
+const DefaultTTL = time.Hour
+
+// New returns a cache expiring its entries after DefaultTTL.
+func New[T any]() *Cache[T] {
+	return &Cache[T]{TTL: DefaultTTL}
+}
+
+// Cache loads every key at most once per TTL - concurrent callers of the same key wait for the pending load.
+// Failed loads aren't cached. The zero value is ready to use and never expires its entries.
+type Cache[T any] struct {
+	// TTL of loaded values - 0 keeps them for the lifetime of the cache
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry[T]
+}
+
+type entry[T any] struct {
+	done     chan struct{}
+	value    T
+	err      error
+	loadedAt time.Time
+	// canceled is set if the load failed because the ctx of the loading caller was canceled
+	canceled bool
+}
+
+// Get returns the cached value of key or loads it.
+// A canceled ctx only aborts waiting for the value, not the load of another caller - waiters take over the load
+// if the loading caller was canceled.
+func (c *Cache[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	for {
+		c.mu.Lock()
+
+		if c.entries == nil {
+			c.entries = make(map[string]*entry[T])
+		}
+
+		e, ok := c.entries[key]
+		if ok && c.isExpired(e) {
+			ok = false
+		}
+
+		if !ok {
+			e = &entry[T]{done: make(chan struct{})}
+			c.entries[key] = e
+			c.mu.Unlock()
+
+			e.value, e.err = load(ctx)
+			e.loadedAt = time.Now()
+			e.canceled = e.err != nil && ctx.Err() != nil
+			close(e.done)
+
+			if e.err != nil {
+				c.forget(key, e)
+			}
+
+			return e.value, e.err
+		}
+
+		c.mu.Unlock()
+
+		select {
+		case <-e.done:
+			if e.canceled && ctx.Err() == nil {
+				continue
+			}
+
+			return e.value, e.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// isExpired must be called with c.mu held - pending loads never expire.
+func (c *Cache[T]) isExpired(e *entry[T]) bool {
+	select {
+	case <-e.done:
+		return e.err != nil || (c.TTL > 0 && time.Since(e.loadedAt) > c.TTL)
+	default:
+		return false
+	}
+}
+
+func (c *Cache[T]) forget(key string, e *entry[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[key] == e {
+		delete(c.entries, key)
+	}
+}
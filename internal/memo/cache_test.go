@@ -0,0 +1,118 @@
+package memo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/internal/memo"
+	"github.com/prskr/aucs/internal/testx"
+)
+
+func TestCache_Get_LoadsOnce(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cache memo.Cache[int]
+		loads atomic.Int32
+		wg    sync.WaitGroup
+	)
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, err := cache.Get(testx.Context(t), "key", func(context.Context) (int, error) {
+				loads.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 42, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 42, got)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func TestCache_Get_ErrorsAreNotCached(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cache   memo.Cache[string]
+		errLoad = errors.New("load failed")
+	)
+
+	_, err := cache.Get(testx.Context(t), "key", func(context.Context) (string, error) {
+		return "", errLoad
+	})
+	assert.ErrorIs(t, err, errLoad)
+
+	got, err := cache.Get(testx.Context(t), "key", func(context.Context) (string, error) {
+		return "value", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "value", got)
+}
+
+func TestCache_Get_ExpiredEntriesAreReloaded(t *testing.T) {
+	t.Parallel()
+
+	cache := memo.Cache[int]{TTL: time.Millisecond}
+
+	first, _ := cache.Get(testx.Context(t), "key", func(context.Context) (int, error) { return 1, nil })
+	time.Sleep(5 * time.Millisecond)
+	second, _ := cache.Get(testx.Context(t), "key", func(context.Context) (int, error) { return 2, nil })
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
+
+func TestCache_Get_WaitersTakeOverCanceledLoads(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cache   memo.Cache[int]
+		started = make(chan struct{})
+	)
+
+	firstCtx, cancelFirst := context.WithCancel(testx.Context(t))
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := cache.Get(firstCtx, "key", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		firstErr <- err
+	}()
+
+	<-started
+
+	secondResult := make(chan int, 1)
+
+	go func() {
+		got, err := cache.Get(testx.Context(t), "key", func(context.Context) (int, error) {
+			return 42, nil
+		})
+		assert.NoError(t, err)
+		secondResult <- got
+	}()
+
+	// give the second caller time to wait for the pending load
+	time.Sleep(10 * time.Millisecond)
+	cancelFirst()
+
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	assert.Equal(t, 42, <-secondResult)
+}
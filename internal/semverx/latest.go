@@ -0,0 +1,42 @@
+package semverx
+
+import "github.com/Masterminds/semver/v3"
+
+// Latest returns the greatest stable version and the greatest pre-release if it's newer than the stable one.
+// Versions parse rejects are skipped - the originals of the parsed versions are returned.
+// Packages with pre-releases only report the greatest pre-release as stable version.
+func Latest(versions []string, parse func(version string) (*semver.Version, error)) (stable, preRelease string) {
+	var latestStable, latestPreRelease *semver.Version
+
+	for _, v := range versions {
+		parsed, err := parse(v)
+		if err != nil {
+			continue
+		}
+
+		if parsed.Prerelease() == "" {
+			latestStable = greater(latestStable, parsed)
+		} else {
+			latestPreRelease = greater(latestPreRelease, parsed)
+		}
+	}
+
+	switch {
+	case latestStable == nil && latestPreRelease == nil:
+		return "", ""
+	case latestStable == nil:
+		return latestPreRelease.Original(), ""
+	case latestPreRelease != nil && latestPreRelease.GreaterThan(latestStable):
+		return latestStable.Original(), latestPreRelease.Original()
+	default:
+		return latestStable.Original(), ""
+	}
+}
+
+func greater(current, candidate *semver.Version) *semver.Version {
+	if current == nil || candidate.GreaterThan(current) {
+		return candidate
+	}
+
+	return current
+}
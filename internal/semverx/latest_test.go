@@ -0,0 +1,76 @@
+package semverx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/internal/semverx"
+)
+
+func TestLatest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		versions       []string
+		parse          func(version string) (*semver.Version, error)
+		wantStable     string
+		wantPreRelease string
+	}{
+		{
+			name:       "Greatest stable version",
+			versions:   []string{"1.2.0", "1.10.0", "1.9.3"},
+			parse:      semver.NewVersion,
+			wantStable: "1.10.0",
+		},
+		{
+			name:           "Newer pre-release",
+			versions:       []string{"1.2.0", "2.0.0-rc.1", "2.0.0-beta.2"},
+			parse:          semver.NewVersion,
+			wantStable:     "1.2.0",
+			wantPreRelease: "2.0.0-rc.1",
+		},
+		{
+			name:       "Older pre-release",
+			versions:   []string{"2.0.0-rc.1", "2.0.0"},
+			parse:      semver.NewVersion,
+			wantStable: "2.0.0",
+		},
+		{
+			name:       "Pre-releases only",
+			versions:   []string{"0.1.0-alpha", "0.1.0-beta"},
+			parse:      semver.NewVersion,
+			wantStable: "0.1.0-beta",
+		},
+		{
+			name:       "Original of parsed version",
+			versions:   []string{"v1.0", "v1.1"},
+			parse:      semver.NewVersion,
+			wantStable: "v1.1",
+		},
+		{
+			name:     "Rejected versions are skipped",
+			versions: []string{"latest", "1.0", "v2.0.0"},
+			parse: func(version string) (*semver.Version, error) {
+				return semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+			},
+			wantStable: "2.0.0",
+		},
+		{
+			name:  "No versions",
+			parse: semver.NewVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stable, preRelease := semverx.Latest(tt.versions, tt.parse)
+			assert.Equal(t, tt.wantStable, stable)
+			assert.Equal(t, tt.wantPreRelease, preRelease)
+		})
+	}
+}
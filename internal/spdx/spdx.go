@@ -0,0 +1,161 @@
+// Package spdx normalizes the free-form license declarations of package registries to SPDX identifiers.
+package spdx
+
+import (
+	"strings"
+)
+
+// licenseIDs are the SPDX identifiers of commonly used licenses indexed by their lower case form.
+var licenseIDs = indexByLowerCase(
+	"0BSD", "AFL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0", "Artistic-2.0",
+	"BlueOak-1.0.0", "BSD-2-Clause", "BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0",
+	"BUSL-1.1", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1", "ELv2",
+	"Elastic-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2", "GPL-2.0-only", "GPL-2.0-or-later",
+	"GPL-3.0-only", "GPL-3.0-or-later", "ISC", "LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1-only",
+	"LGPL-2.1-or-later", "LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MIT-0", "MPL-1.1", "MPL-2.0",
+	"MS-PL", "MS-RL", "NCSA", "ODbL-1.0", "OFL-1.1", "OpenSSL", "PostgreSQL", "PSF-2.0", "Python-2.0",
+	"SSPL-1.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0", "WTFPL", "X11", "Zlib", "ZPL-2.1",
+)
+
+// aliases maps common non-SPDX license names - in lower case - to their SPDX identifiers.
+var aliases = map[string]string{
+	"mit license":                 "MIT",
+	"the mit license":             "MIT",
+	"expat":                       "MIT",
+	"isc license":                 "ISC",
+	"apache 2":                    "Apache-2.0",
+	"apache 2.0":                  "Apache-2.0",
+	"apache-2":                    "Apache-2.0",
+	"apache license 2.0":          "Apache-2.0",
+	"apache license, version 2.0": "Apache-2.0",
+	"apache software license":     "Apache-2.0",
+	"the apache software license, version 2.0": "Apache-2.0",
+	"asl 2.0":                            "Apache-2.0",
+	"bsd license":                        "BSD-3-Clause",
+	"new bsd license":                    "BSD-3-Clause",
+	"3-clause bsd license":               "BSD-3-Clause",
+	"bsd-3":                              "BSD-3-Clause",
+	"simplified bsd license":             "BSD-2-Clause",
+	"bsd-2":                              "BSD-2-Clause",
+	"boost software license 1.0":         "BSL-1.0",
+	"business source license 1.1":        "BUSL-1.1",
+	"bsl-1.1":                            "BUSL-1.1",
+	"elastic license 2.0":                "Elastic-2.0",
+	"gpl-2.0":                            "GPL-2.0-only",
+	"gpl-2.0+":                           "GPL-2.0-or-later",
+	"gplv2":                              "GPL-2.0-only",
+	"gpl-3.0":                            "GPL-3.0-only",
+	"gpl-3.0+":                           "GPL-3.0-or-later",
+	"gplv3":                              "GPL-3.0-only",
+	"lgpl-2.1":                           "LGPL-2.1-only",
+	"lgpl-2.1+":                          "LGPL-2.1-or-later",
+	"lgpl-3.0":                           "LGPL-3.0-only",
+	"lgpl-3.0+":                          "LGPL-3.0-or-later",
+	"lgplv3":                             "LGPL-3.0-only",
+	"agpl-3.0":                           "AGPL-3.0-only",
+	"agplv3":                             "AGPL-3.0-only",
+	"mozilla public license 2.0":         "MPL-2.0",
+	"mpl 2.0":                            "MPL-2.0",
+	"eclipse public license 2.0":         "EPL-2.0",
+	"python software foundation license": "PSF-2.0",
+	"psf":                                "PSF-2.0",
+	"the unlicense":                      "Unlicense",
+	"zlib license":                       "Zlib",
+	"server side public license":         "SSPL-1.0",
+}
+
+// classifiers maps PyPI trove classifiers to SPDX identifiers - classifiers without a unique identifier are omitted.
+var classifiers = map[string]string{
+	"License :: OSI Approved :: Apache Software License":                                 "Apache-2.0",
+	"License :: OSI Approved :: BSD License":                                             "BSD-3-Clause",
+	"License :: OSI Approved :: GNU Affero General Public License v3":                    "AGPL-3.0-only",
+	"License :: OSI Approved :: GNU General Public License v2 (GPLv2)":                   "GPL-2.0-only",
+	"License :: OSI Approved :: GNU General Public License v3 (GPLv3)":                   "GPL-3.0-only",
+	"License :: OSI Approved :: GNU Lesser General Public License v3 (LGPLv3)":           "LGPL-3.0-only",
+	"License :: OSI Approved :: ISC License (ISCL)":                                      "ISC",
+	"License :: OSI Approved :: MIT License":                                             "MIT",
+	"License :: OSI Approved :: Mozilla Public License 2.0 (MPL 2.0)":                    "MPL-2.0",
+	"License :: OSI Approved :: Python Software Foundation License":                      "PSF-2.0",
+	"License :: OSI Approved :: The Unlicense (Unlicense)":                               "Unlicense",
+	"License :: OSI Approved :: zlib/libpng License":                                     "Zlib",
+	"License :: Other/Proprietary License":                                               "LicenseRef-Proprietary",
+	"License :: CC0 1.0 Universal (CC0 1.0) Public Domain Dedication":                    "CC0-1.0",
+	"License :: OSI Approved :: Boost Software License 1.0 (BSL-1.0)":                    "BSL-1.0",
+	"License :: OSI Approved :: Eclipse Public License 2.0 (EPL-2.0)":                    "EPL-2.0",
+	"License :: OSI Approved :: European Union Public Licence 1.2 (EUPL 1.2)":            "EUPL-1.2",
+	"License :: OSI Approved :: GNU Library or Lesser General Public License (LGPL)":     "LGPL-2.0-or-later",
+	"License :: OSI Approved :: Universal Permissive License (UPL)":                      "UPL-1.0",
+	"License :: OSI Approved :: GNU General Public License v3 or later (GPLv3+)":         "GPL-3.0-or-later",
+	"License :: OSI Approved :: GNU General Public License v2 or later (GPLv2+)":         "GPL-2.0-or-later",
+	"License :: OSI Approved :: GNU Lesser General Public License v2 or later (LGPLv2+)": "LGPL-2.0-or-later",
+	"License :: OSI Approved :: GNU Lesser General Public License v3 or later (LGPLv3+)": "LGPL-3.0-or-later",
+}
+
+// Normalize converts a license name or SPDX expression to its canonical SPDX form.
+// Operands of expressions are normalized individually, unknown licenses are kept as they are.
+func Normalize(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return ""
+	}
+
+	if id, ok := normalizeID(license); ok {
+		return id
+	}
+
+	fields := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(license))
+	for i, f := range fields {
+		switch strings.ToUpper(f) {
+		case "AND", "OR", "WITH":
+			fields[i] = strings.ToUpper(f)
+		case "(", ")":
+		default:
+			if id, ok := normalizeID(f); ok {
+				fields[i] = id
+			}
+		}
+	}
+
+	return strings.NewReplacer("( ", "(", " )", ")").Replace(strings.Join(fields, " "))
+}
+
+// FromClassifiers returns the license declared by PyPI trove classifiers - multiple licenses are combined with OR.
+func FromClassifiers(troveClassifiers []string) string {
+	var ids []string
+
+	for _, c := range troveClassifiers {
+		if id, ok := classifiers[c]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return strings.Join(ids, " OR ")
+}
+
+// Equal compares two licenses independent of their notation.
+func Equal(a, b string) bool {
+	return strings.EqualFold(Normalize(a), Normalize(b))
+}
+
+func normalizeID(license string) (string, bool) {
+	lower := strings.ToLower(license)
+
+	if id, ok := licenseIDs[lower]; ok {
+		return id, true
+	}
+
+	if id, ok := aliases[lower]; ok {
+		return id, true
+	}
+
+	return "", false
+}
+
+func indexByLowerCase(ids ...string) map[string]string {
+	index := make(map[string]string, len(ids))
+	for _, id := range ids {
+		index[strings.ToLower(id)] = id
+	}
+
+	return index
+}
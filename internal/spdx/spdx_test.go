@@ -0,0 +1,55 @@
+package spdx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prskr/aucs/internal/spdx"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		license string
+		want    string
+	}{
+		{license: "", want: ""},
+		{license: "mit", want: "MIT"},
+		{license: " MIT License ", want: "MIT"},
+		{license: "Apache License, Version 2.0", want: "Apache-2.0"},
+		{license: "apache-2.0 or mit", want: "Apache-2.0 OR MIT"},
+		{license: "(MIT AND bsd-3-clause)", want: "(MIT AND BSD-3-Clause)"},
+		{license: "GPL-2.0+ WITH Classpath-exception-2.0", want: "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+		{license: "Business Source License 1.1", want: "BUSL-1.1"},
+		{license: "SEE LICENSE IN LICENSE.md", want: "SEE LICENSE IN LICENSE.md"},
+		{license: "BSD", want: "BSD"},
+		{license: "Public Domain", want: "Public Domain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, spdx.Normalize(tt.license))
+		})
+	}
+}
+
+func TestFromClassifiers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "MIT OR Apache-2.0", spdx.FromClassifiers([]string{
+		"Development Status :: 5 - Production/Stable",
+		"License :: OSI Approved :: MIT License",
+		"License :: OSI Approved :: Apache Software License",
+	}))
+	assert.Empty(t, spdx.FromClassifiers([]string{"License :: OSI Approved"}))
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, spdx.Equal("MIT License", "mit"))
+	assert.False(t, spdx.Equal("MIT", "BUSL-1.1"))
+}
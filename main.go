@@ -2,17 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/alecthomas/kong"
 
 	"github.com/prskr/aucs/core/ports"
 	"github.com/prskr/aucs/handlers/cli"
+	"github.com/prskr/aucs/infrastructure/checker/cocoapods"
+	"github.com/prskr/aucs/infrastructure/checker/conan"
+	"github.com/prskr/aucs/infrastructure/checker/conda"
+	"github.com/prskr/aucs/infrastructure/checker/cran"
+	"github.com/prskr/aucs/infrastructure/checker/github"
+	"github.com/prskr/aucs/infrastructure/checker/hex"
+	"github.com/prskr/aucs/infrastructure/checker/pub"
+	"github.com/prskr/aucs/infrastructure/checker/terraform"
 	"github.com/prskr/aucs/infrastructure/config"
+	"github.com/prskr/aucs/infrastructure/telemetry"
+	"github.com/prskr/aucs/infrastructure/vulnerability/osv"
 )
 
 func main() {
@@ -28,7 +40,19 @@ func main() {
 	os.Exit(exitCode)
 }
 
-func run(ctx context.Context) error {
+func run(ctx context.Context) (err error) {
+	shutdownTracing, err := telemetry.SetupTracing(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		err = errors.Join(err, shutdownTracing(shutdownCtx))
+	}()
+
 	var app App
 
 	kongCtx := kong.Parse(
@@ -37,8 +61,19 @@ func run(ctx context.Context) error {
 		kong.Description("A simple library application for working with messaging"),
 		kong.BindTo(ctx, (*context.Context)(nil)),
 		kong.BindTo(os.Stdout, (*ports.STDOUT)(nil)),
+		kong.BindTo(os.Stderr, (*ports.STDERR)(nil)),
 		kong.Vars{
-			"XDG_CACHE_HOME": filepath.ToSlash(xdg.CacheHome),
+			"XDG_CACHE_HOME":        filepath.ToSlash(xdg.CacheHome),
+			"OSV_BASE_URL":          osv.DefaultBaseURL,
+			"HEX_BASE_URL":          hex.DefaultBaseURL,
+			"PUB_BASE_URL":          pub.DefaultBaseURL,
+			"GITHUB_BASE_URL":       github.DefaultBaseURL,
+			"TERRAFORM_BASE_URL":    terraform.DefaultBaseURL,
+			"CONDA_CHANNEL_ALIAS":   conda.DefaultChannelAlias,
+			"COCOAPODS_BASE_URL":    cocoapods.DefaultBaseURL,
+			"CONAN_BASE_URL":        conan.DefaultBaseURL,
+			"CRAN_BASE_URL":         cran.DefaultBaseURL,
+			"BIOCONDUCTOR_BASE_URL": cran.DefaultBioconductorBaseURL,
 		},
 	)
 
@@ -49,10 +84,12 @@ type App struct {
 	Logging config.Logging `embed:"" prefix:"logging."`
 
 	Enrich cli.EnrichCLiHandler `cmd:"" help:"Enrich SBOM with available updates" default:"withargs"`
+	Serve  cli.ServeCliHandler  `cmd:"" help:"Serve lookups and SBOM enrichment via HTTP"`
+	Cache  cli.CacheCliHandler  `cmd:"" help:"Manage the local lookup cache"`
 }
 
 func (a *App) AfterApply(kongCtx *kong.Context) error {
-	defaultLogger := slog.New(slog.NewJSONHandler(os.Stderr, a.Logging.Options()))
+	defaultLogger := slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(os.Stderr, a.Logging.Options())))
 
 	slog.SetDefault(defaultLogger)
 	kongCtx.Bind(defaultLogger)